@@ -0,0 +1,212 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NGINX/Apache combined log format, common log format is matched too
+var combinedLogRegexp = regexp.MustCompile(`^\S+ \S+ \S+ \[([^\]]+)\] "(\S+) (\S+)(?: [^"]*)?" \d{3} \S+(?: "([^"]*)" "([^"]*)")?`)
+
+const combinedLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// Field names we look for in JSON access logs, first found wins
+var (
+	jsonLogTimeFields      = []string{"time", "time_iso8601", "time_local", "timestamp", "@timestamp", "msec"}
+	jsonLogMethodFields    = []string{"method", "request_method"}
+	jsonLogURIFields       = []string{"request_uri", "uri", "path", "url"}
+	jsonLogRequestFields   = []string{"request"}
+	jsonLogUserAgentFields = []string{"http_user_agent", "user_agent"}
+	jsonLogRefererFields   = []string{"http_referer", "referer"}
+)
+
+// Single parsed access log line
+type accessLogEntry struct {
+	time      time.Time
+	method    string
+	uri       string
+	userAgent string
+	referer   string
+}
+
+// Load access log in combined or JSON lines format into a request list against the base URL
+func loadAccessLog(fileName string, baseURL string) ([]requestSpec, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := make([]accessLogEntry, 0)
+	skipped := 0
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry accessLogEntry
+		if strings.HasPrefix(line, "{") {
+			entry, err = parseJSONLogLine(line)
+		} else {
+			entry, err = parseCombinedLogLine(line)
+		}
+
+		if err != nil {
+			applog.Infof("Skipping access log line %q: %s", line, err.Error())
+			skipped++
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if skipped > 0 {
+		applog.Warningf("Skipped %v access log lines which could not be parsed", skipped)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no access log lines to replay")
+	}
+
+	// Logs are written when requests are done, so lines are not always in order
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].time.Before(entries[j].time)
+	})
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	requests := make([]requestSpec, 0, len(entries))
+	for _, entry := range entries {
+		request := requestSpec{
+			method:  entry.method,
+			url:     baseURL + entry.uri,
+			headers: make(httpHeaders),
+			offset:  entry.time.Sub(entries[0].time),
+		}
+
+		if entry.userAgent != "" && entry.userAgent != "-" {
+			request.headers["User-Agent"] = entry.userAgent
+		}
+		if entry.referer != "" && entry.referer != "-" {
+			request.headers["Referer"] = entry.referer
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// Parse combined log format line
+func parseCombinedLogLine(line string) (accessLogEntry, error) {
+	m := combinedLogRegexp.FindStringSubmatch(line)
+	if m == nil {
+		return accessLogEntry{}, fmt.Errorf("line doesn't match combined log format")
+	}
+
+	t, err := time.Parse(combinedLogTimeFormat, m[1])
+	if err != nil {
+		return accessLogEntry{}, err
+	}
+
+	entry := accessLogEntry{time: t, method: m[2], uri: m[3], referer: m[4], userAgent: m[5]}
+
+	return entry, validateAccessLogEntry(entry)
+}
+
+// Parse JSON access log line
+func parseJSONLogLine(line string) (accessLogEntry, error) {
+	var fields map[string]any
+	var entry accessLogEntry
+
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return entry, err
+	}
+
+	t, err := parseAccessLogTime(jsonLogField(fields, jsonLogTimeFields))
+	if err != nil {
+		return entry, err
+	}
+	entry.time = t
+
+	entry.method = jsonLogField(fields, jsonLogMethodFields)
+	entry.uri = jsonLogField(fields, jsonLogURIFields)
+
+	// Request line, like "GET /path HTTP/1.1"
+	if request := strings.Fields(jsonLogField(fields, jsonLogRequestFields)); len(request) >= 2 {
+		if entry.method == "" {
+			entry.method = request[0]
+		}
+		if entry.uri == "" {
+			entry.uri = request[1]
+		}
+	}
+
+	entry.userAgent = jsonLogField(fields, jsonLogUserAgentFields)
+	entry.referer = jsonLogField(fields, jsonLogRefererFields)
+
+	return entry, validateAccessLogEntry(entry)
+}
+
+// Get first found field as string
+func jsonLogField(fields map[string]any, names []string) string {
+	for _, name := range names {
+		switch value := fields[name].(type) {
+		case string:
+			return value
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	}
+
+	return ""
+}
+
+// Parse access log time in RFC3339, combined log format or UNIX timestamp with fractions
+func parseAccessLogTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("no time field")
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(combinedLogTimeFormat, value); err == nil {
+		return t, nil
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Unix(0, int64(seconds*float64(time.Second))), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported time format %q", value)
+}
+
+// We can replay only requests with method and path
+func validateAccessLogEntry(entry accessLogEntry) error {
+	if entry.method == "" || !strings.HasPrefix(entry.uri, "/") {
+		return fmt.Errorf("no method or path")
+	}
+
+	return nil
+}
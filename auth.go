@@ -0,0 +1,340 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Auth provider gives Authorization header value for benchmarked requests. Providers are shared by all workers
+type authProvider interface {
+	authorization() (string, error)
+}
+
+// Get Authorization header value for the next request. It's called before the request is timed, so token fetch
+// latency is not a part of the request duration
+func requestAuthorization(config appConfig) (appConfig, error) {
+	if config.authProvider == nil {
+		return config, nil
+	}
+
+	authorization, err := config.authProvider.authorization()
+	config.authorization = authorization
+
+	return config, err
+}
+
+// Custom type to parse "claim=value" JWT claims as multiple flag cli args. Values are JSON or strings
+type jwtClaims map[string]any
+
+func (claims *jwtClaims) String() string {
+	return "JWT claims"
+}
+
+func (claims *jwtClaims) Set(value string) error {
+	name, raw, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("wrong claim argument, expected 'claim=value'")
+	}
+
+	var claim any
+	if err := json.Unmarshal([]byte(raw), &claim); err != nil {
+		claim = raw
+	}
+
+	if *claims == nil {
+		*claims = make(jwtClaims)
+	}
+	(*claims)[name] = claim
+
+	return nil
+}
+
+// Failed token fetches are retried with exponential backoff, so a token endpoint which is down isn't hammered
+const (
+	authRetryBackoff    = 100 * time.Millisecond
+	authRetryBackoffMax = 10 * time.Second
+)
+
+// Cached token with its refresh and expiry times, shared by providers which need refresh
+type authToken struct {
+	mutex     sync.Mutex
+	value     string
+	refreshAt time.Time
+	expiresAt time.Time
+
+	// Last failed fetch, the next one is not tried before retryAt
+	failures int
+	retryAt  time.Time
+	err      error
+}
+
+// Get cached token, or get a new one if there's no token yet or it expires soon. Cached token is used
+// until it expires if the refresh fails. Token fetch latency and failures are tracked separately from benchmarked requests
+func (t *authToken) get(config appConfig, fetch func() (string, time.Time, error)) (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	valid := t.value != "" && (t.expiresAt.IsZero() || now.Before(t.expiresAt))
+	if valid && (t.refreshAt.IsZero() || now.Before(t.refreshAt)) {
+		return t.value, nil
+	}
+
+	// Refresh failed recently, wait for the backoff
+	if now.Before(t.retryAt) {
+		if valid {
+			return t.value, nil
+		}
+		return "", t.err
+	}
+
+	started := time.Now()
+	value, expires, err := fetch()
+	config.metrics.authTokenFetches.WithLabelValues(config.metrics.labelValues...).Inc()
+	config.metrics.histAuthTokenFetchDuration.WithLabelValues(config.metrics.labelValues...).Observe(time.Since(started).Seconds())
+	config.metrics.summaryAuthTokenFetchDuration.WithLabelValues(config.metrics.labelValues...).Observe(time.Since(started).Seconds())
+
+	if err != nil {
+		config.metrics.authTokenFetchErrors.WithLabelValues(config.metrics.labelValues...).Inc()
+		applog.Errorf("Failed to get auth token: %s", err.Error())
+
+		t.failures++
+		t.retryAt = time.Now().Add(min(authRetryBackoff<<min(t.failures-1, 16), authRetryBackoffMax))
+		t.err = err
+		if valid {
+			return t.value, nil
+		}
+		return "", err
+	}
+
+	applog.Infof("Got new auth token in %v, expires at %v", time.Since(started), expires)
+	t.value, t.refreshAt, t.expiresAt = value, time.Time{}, expires
+	t.failures, t.retryAt, t.err = 0, time.Time{}, nil
+
+	// Refresh before the token expires, but not too often for short lived tokens
+	if !expires.IsZero() {
+		lifetime := time.Until(expires)
+		t.refreshAt = expires.Add(-min(config.authRefreshBefore, lifetime/2))
+	}
+
+	return value, nil
+}
+
+// Static basic auth
+type basicAuthProvider struct {
+	value string
+}
+
+func (p *basicAuthProvider) authorization() (string, error) {
+	return p.value, nil
+}
+
+// OAuth2 client credentials flow, token is refreshed before it expires
+type oauth2AuthProvider struct {
+	config appConfig
+	client *http.Client
+	token  authToken
+}
+
+func (p *oauth2AuthProvider) authorization() (string, error) {
+	return p.token.get(p.config, p.fetch)
+}
+
+// Request a new access token from the token endpoint
+func (p *oauth2AuthProvider) fetch() (string, time.Time, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if p.config.oauth2Scope != "" {
+		form.Set("scope", p.config.oauth2Scope)
+	}
+
+	req, err := http.NewRequest("POST", p.config.oauth2TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.config.oauth2ClientID), url.QueryEscape(p.config.oauth2ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("token endpoint returned status %v", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", time.Time{}, fmt.Errorf("error parsing token response: %s", err.Error())
+	}
+
+	if token.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("no access token in token response")
+	}
+
+	var expires time.Time
+	if token.ExpiresIn > 0 {
+		expires = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	// Token type is case insensitive, but some servers are strict about "Bearer"
+	tokenType := token.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+
+	return tokenType + " " + token.AccessToken, expires, nil
+}
+
+// Locally signed JWT, new token is signed before the previous one expires
+type jwtAuthProvider struct {
+	config appConfig
+	secret []byte
+	key    *rsa.PrivateKey
+	token  authToken
+}
+
+func (p *jwtAuthProvider) authorization() (string, error) {
+	return p.token.get(p.config, p.sign)
+}
+
+// Sign a new token with configured claims
+func (p *jwtAuthProvider) sign() (string, time.Time, error) {
+	now := time.Now()
+	expires := now.Add(p.config.jwtTTL)
+
+	claims := map[string]any{"iat": now.Unix(), "exp": expires.Unix()}
+	for name, value := range p.config.jwtClaims {
+		claims[name] = value
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": p.config.jwtAlg, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch p.config.jwtAlg {
+	case "HS256":
+		mac := hmac.New(sha256.New, p.secret)
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case "RS256":
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:]); err != nil {
+			return "", time.Time{}, err
+		}
+	}
+
+	return "Bearer " + signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), expires, nil
+}
+
+// Init auth provider from config, returns nil if auth is not configured
+func initAuthProvider(config appConfig) (authProvider, error) {
+	switch config.auth {
+	case "":
+		return nil, nil
+
+	case "basic":
+		if !strings.Contains(config.authBasic, ":") {
+			return nil, fmt.Errorf("-auth-basic is required in 'user:password' form for basic auth")
+		}
+		return &basicAuthProvider{value: "Basic " + base64.StdEncoding.EncodeToString([]byte(config.authBasic))}, nil
+
+	case "oauth2":
+		if err := validateUrl(config.oauth2TokenURL); err != nil {
+			return nil, fmt.Errorf("wrong -oauth2-token-url: %s", err.Error())
+		}
+		if config.oauth2ClientID == "" {
+			return nil, fmt.Errorf("-oauth2-client-id is required for oauth2 auth")
+		}
+
+		// Token requests don't go through benchmarked clients, so they don't affect the results
+		client := &http.Client{
+			Timeout:   config.sendTimeout,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config.tlsConfig},
+		}
+		return &oauth2AuthProvider{config: config, client: client}, nil
+
+	case "jwt":
+		if config.jwtTTL <= 0 {
+			return nil, fmt.Errorf("-jwt-ttl must be > 0")
+		}
+
+		p := &jwtAuthProvider{config: config}
+		switch config.jwtAlg {
+		case "HS256":
+			if config.jwtSecret == "" {
+				return nil, fmt.Errorf("-jwt-secret is required for HS256")
+			}
+			p.secret = []byte(config.jwtSecret)
+		case "RS256":
+			key, err := loadRSAPrivateKey(config.jwtKey)
+			if err != nil {
+				return nil, fmt.Errorf("error loading -jwt-key: %s", err.Error())
+			}
+			p.key = key
+		default:
+			return nil, fmt.Errorf("unsupported JWT algorithm %q, supported are HS256 and RS256", config.jwtAlg)
+		}
+		return p, nil
+	}
+
+	return nil, fmt.Errorf("unsupported auth %q, supported are basic, oauth2 and jwt", config.auth)
+}
+
+// Load RSA private key in PKCS #1 or PKCS #8 PEM format
+func loadRSAPrivateKey(fileName string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("not an RSA private key")
+	}
+
+	return rsaKey, nil
+}
@@ -0,0 +1,397 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Random body generator modes
+var randomBodyModes = []string{"letters", "bytes", "compressible", "json"}
+
+// From 64 B to 64 MB, request body size per request
+var bodySizeBuckets = []float64{64, 256, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20, 64 << 20}
+
+const randomLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Random body size distribution, parsed from -random-body-size
+type bodySizeDistribution struct {
+	kind   string
+	min    uint64
+	max    uint64
+	mean   float64
+	stddev float64
+
+	// Histogram sizes with cumulative weights
+	sizes   []uint64
+	weights []float64
+}
+
+// Random request body generator. Bodies are taken from the pool if there is one, or generated per request
+type randomBodyGenerator struct {
+	mode      string
+	sizes     bodySizeDistribution
+	jsonDepth int
+	shape     any
+	pool      [][]byte
+}
+
+// Parse body size distribution: "1KB" or "fixed:1KB", "uniform:1KB-10KB", "normal:10KB,2KB" with mean and
+// standard deviation, or "histogram:file" with "size weight" lines
+func parseBodySizeDistribution(value string) (bodySizeDistribution, error) {
+	kind, spec, ok := strings.Cut(value, ":")
+	if !ok {
+		kind, spec = "fixed", value
+	}
+
+	d := bodySizeDistribution{kind: kind}
+	var err error
+
+	switch kind {
+	case "fixed":
+		d.min, err = humanize.ParseBytes(spec)
+		d.max, d.mean = d.min, float64(d.min)
+
+	case "uniform":
+		from, to, ok := strings.Cut(spec, "-")
+		if !ok {
+			return d, fmt.Errorf("expected uniform:min-max, got %q", value)
+		}
+		if d.min, err = humanize.ParseBytes(from); err == nil {
+			d.max, err = humanize.ParseBytes(to)
+		}
+		if err == nil && d.max < d.min {
+			err = fmt.Errorf("max size is less than min size")
+		}
+		d.mean = float64(d.min+d.max) / 2
+
+	case "normal":
+		mean, stddev, ok := strings.Cut(spec, ",")
+		if !ok {
+			return d, fmt.Errorf("expected normal:mean,stddev, got %q", value)
+		}
+		var m, s uint64
+		if m, err = humanize.ParseBytes(mean); err == nil {
+			s, err = humanize.ParseBytes(stddev)
+		}
+		d.mean, d.stddev = float64(m), float64(s)
+
+	case "histogram":
+		err = d.loadHistogram(spec)
+
+	default:
+		return d, fmt.Errorf("unsupported size distribution %q, supported are fixed, uniform, normal and histogram", kind)
+	}
+
+	if err != nil {
+		return d, fmt.Errorf("error parsing %q: %s", value, err.Error())
+	}
+
+	return d, nil
+}
+
+// Load histogram file, every line is a size and its weight, like "10KB 25". Empty lines and # comments are skipped
+func (d *bodySizeDistribution) loadHistogram(fileName string) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var total, weighted float64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return fmt.Errorf("wrong histogram line %q, expected 'size weight'", line)
+		}
+
+		size, err := humanize.ParseBytes(fields[0])
+		if err != nil {
+			return err
+		}
+		weight, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || weight < 0 {
+			return fmt.Errorf("wrong weight in histogram line %q", line)
+		}
+
+		total += weight
+		weighted += weight * float64(size)
+		d.sizes = append(d.sizes, size)
+		d.weights = append(d.weights, total)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if total == 0 {
+		return fmt.Errorf("histogram has no weights")
+	}
+	d.mean = weighted / total
+
+	return nil
+}
+
+// Random size of the distribution, normal distribution is cut at 0
+func (d bodySizeDistribution) sample(rng *rand.Rand) uint64 {
+	switch d.kind {
+	case "uniform":
+		return d.min + uint64(rng.Int63n(int64(d.max-d.min+1)))
+	case "normal":
+		return uint64(math.Max(0, math.Round(d.mean+rng.NormFloat64()*d.stddev)))
+	case "histogram":
+		i := sort.SearchFloat64s(d.weights, rng.Float64()*d.weights[len(d.weights)-1])
+		return d.sizes[min(i, len(d.sizes)-1)]
+	}
+
+	return d.min
+}
+
+func (d bodySizeDistribution) String() string {
+	switch d.kind {
+	case "uniform":
+		return fmt.Sprintf("uniform %v to %v", humanizeBytes(int64(d.min), false), humanizeBytes(int64(d.max), false))
+	case "normal":
+		return fmt.Sprintf("normal, mean %v, stddev %v", humanizeBytes(int64(d.mean), false), humanizeBytes(int64(d.stddev), false))
+	case "histogram":
+		return fmt.Sprintf("histogram of %v sizes, mean %v", len(d.sizes), humanizeBytes(int64(d.mean), false))
+	}
+
+	return humanizeBytes(int64(d.min), false)
+}
+
+// New generator, without a pool bodies are generated per request
+func newRandomBodyGenerator(mode string, sizes bodySizeDistribution, jsonDepth int, shapeFile string) (*randomBodyGenerator, error) {
+	g := &randomBodyGenerator{mode: mode, sizes: sizes, jsonDepth: jsonDepth}
+
+	supported := false
+	for _, m := range randomBodyModes {
+		supported = supported || m == mode
+	}
+	if !supported {
+		return nil, fmt.Errorf("unsupported mode %q, supported are %s", mode, strings.Join(randomBodyModes, ", "))
+	}
+
+	if shapeFile != "" {
+		if mode != "json" {
+			return nil, fmt.Errorf("shape is used by json mode only")
+		}
+		data, err := os.ReadFile(shapeFile)
+		if err != nil {
+			return nil, err
+		}
+		if g.shape, err = decodeJSONPayload(data); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// Generate the pool of bodies, sizes are drawn from the distribution
+func (g *randomBodyGenerator) fillPool(rng *rand.Rand, size int) {
+	g.pool = make([][]byte, size)
+	for i := range g.pool {
+		g.pool[i] = g.generate(rng, g.sizes.sample(rng))
+	}
+}
+
+// Body for the next request
+func (g *randomBodyGenerator) next(rng *rand.Rand) []byte {
+	if len(g.pool) > 0 {
+		return g.pool[rng.Intn(len(g.pool))]
+	}
+
+	return g.generate(rng, g.sizes.sample(rng))
+}
+
+// Generate a body of the size
+func (g *randomBodyGenerator) generate(rng *rand.Rand, size uint64) []byte {
+	switch g.mode {
+	case "bytes":
+		// Random bytes don't compress
+		result := make([]byte, size)
+		rng.Read(result)
+		return result
+
+	case "compressible":
+		return generateCompressible(rng, size)
+
+	case "json":
+		if g.shape != nil {
+			return generateJSONLike(rng, g.shape, size)
+		}
+		var buf bytes.Buffer
+		writeRandomJSONObject(&buf, rng, int(size), max(g.jsonDepth, 1))
+		return buf.Bytes()
+	}
+
+	return generateLetters(rng, size)
+}
+
+// Random ASCII letters
+func generateLetters(rng *rand.Rand, size uint64) []byte {
+	result := make([]byte, size)
+	for i := range result {
+		result[i] = randomLetters[rng.Intn(len(randomLetters))]
+	}
+
+	return result
+}
+
+// Text of a few random words repeated in random order, it compresses like natural text does
+func generateCompressible(rng *rand.Rand, size uint64) []byte {
+	words := make([][]byte, 16)
+	for i := range words {
+		words[i] = generateLetters(rng, uint64(3+rng.Intn(6)))
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(int(size))
+	for uint64(buf.Len()) < size {
+		buf.Write(words[rng.Intn(len(words))])
+		buf.WriteByte(' ')
+	}
+
+	return buf.Bytes()[:size]
+}
+
+// Smallest room for one more field: comma, key of up to 14 bytes with quotes and colon, and an empty string
+const minJSONField = 20
+
+// Random JSON object of exactly the size, with nested objects up to the depth. Objects smaller than "{}" are "{}"
+func writeRandomJSONObject(buf *bytes.Buffer, rng *rand.Rand, size int, depth int) {
+	end := buf.Len() + size - 1
+	buf.WriteByte('{')
+
+	for i := 0; ; i++ {
+		key := fmt.Sprintf(`"%s%d":`, generateLetters(rng, 4), i)
+		separator := 0
+		if i > 0 {
+			separator = 1
+		}
+
+		remaining := end - buf.Len() - separator - len(key)
+		if remaining < 2 {
+			break
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(key)
+
+		// Field which would leave no room for the next one takes the rest
+		switch length := 2 + rng.Intn(62); {
+		case remaining-length < minJSONField:
+			writeRandomJSONString(buf, rng, remaining)
+		case depth > 1 && remaining >= 4*minJSONField && rng.Intn(4) == 0:
+			writeRandomJSONObject(buf, rng, minJSONField+rng.Intn(remaining/2), depth-1)
+		case rng.Intn(3) == 0:
+			buf.WriteString(strconv.Itoa(rng.Intn(1000000)))
+		default:
+			writeRandomJSONString(buf, rng, length)
+		}
+	}
+
+	buf.WriteByte('}')
+}
+
+// JSON string of the size with quotes
+func writeRandomJSONString(buf *bytes.Buffer, rng *rand.Rand, size int) {
+	buf.WriteByte('"')
+	buf.Write(generateLetters(rng, uint64(max(size-2, 0))))
+	buf.WriteByte('"')
+}
+
+// Random JSON document of the shape: strings of the same length, numbers of the same magnitude, and the first
+// array in the document grown until the document is about the size
+func generateJSONLike(rng *rand.Rand, shape any, size uint64) []byte {
+	generate := func(extra int) []byte {
+		grown := false
+		data, _ := json.Marshal(randomLike(rng, shape, extra, &grown))
+		return data
+	}
+
+	data := generate(0)
+	if uint64(len(data)) >= size {
+		return data
+	}
+
+	// Size of an array item, if there is an array to grow
+	itemSize := len(generate(1)) - len(data)
+	if itemSize <= 0 {
+		return data
+	}
+
+	return generate(int(size-uint64(len(data))) / itemSize)
+}
+
+// Random value like the shape one
+func randomLike(rng *rand.Rand, shape any, extra int, grown *bool) any {
+	switch v := shape.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for _, key := range sortedKeys(v) {
+			result[key] = randomLike(rng, v[key], extra, grown)
+		}
+		return result
+
+	case []any:
+		if len(v) == 0 {
+			return v
+		}
+		items := len(v)
+		if !*grown {
+			items += extra
+			*grown = true
+		}
+		result := make([]any, items)
+		for i := range result {
+			result[i] = randomLike(rng, v[i%len(v)], extra, grown)
+		}
+		return result
+
+	case string:
+		return string(generateLetters(rng, uint64(len(v))))
+
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			// Same number of digits and sign
+			limit := int64(math.Pow10(len(strconv.FormatInt(max(i, -i), 10))))
+			if limit <= 0 {
+				limit = math.MaxInt64
+			}
+			n := rng.Int63n(limit)
+			if i < 0 {
+				n = -n
+			}
+			return json.Number(strconv.FormatInt(n, 10))
+		}
+		f, _ := v.Float64()
+		return json.Number(strconv.FormatFloat(rng.Float64()*2*f, 'f', -1, 64))
+
+	case bool:
+		return rng.Intn(2) == 0
+	}
+
+	return shape
+}
@@ -0,0 +1,160 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Connection with lifecycle info, so we can close it after N requests or when it's too old
+type trackedConn struct {
+	net.Conn
+	created  time.Time
+	requests atomic.Int64
+}
+
+// Limits rate of new connections across all workers
+type connRateLimiter struct {
+	tokens chan struct{}
+}
+
+// Do we need to control connections lifecycle
+func useConnChurn(config appConfig) bool {
+	return config.connMaxRequests > 0 || config.connMaxAge > 0 || config.connLimiter != nil
+}
+
+// Start new connections rate limiter, returns nil if rate is unlimited.
+// It keeps running after benchmark is done, so workers could finish requests waiting for a connection
+func initConnRateLimiter(rate int) *connRateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+
+	limiter := &connRateLimiter{tokens: make(chan struct{}, 1)}
+	tick := time.Tick(time.Second / time.Duration(rate))
+
+	go func() {
+		for range tick {
+			select {
+			case limiter.tokens <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return limiter
+}
+
+// Wait until we're allowed to open a new connection
+func (l *connRateLimiter) wait(ctx context.Context) error {
+	select {
+	case <-l.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Wrap dial function to apply new connections rate limit and to track connections lifecycle
+func withConnChurn(config appConfig, dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if config.connLimiter != nil {
+			if err := config.connLimiter.wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		return &trackedConn{Conn: conn, created: time.Now()}, nil
+	}
+}
+
+// Get our tracked connection from the connection used for a request
+func getTrackedConn(conn net.Conn) *trackedConn {
+	switch c := conn.(type) {
+	case *trackedConn:
+		return c
+	case *tls.Conn:
+		if tc, ok := c.NetConn().(*trackedConn); ok {
+			return tc
+		}
+	}
+
+	return nil
+}
+
+// Connections of a worker by host. Workers send requests one by one, and transports reuse the most recently used
+// idle connection, so the next request to a host is sent over the connection of the previous one
+type connChurn struct {
+	mutex sync.Mutex
+	conns map[string]*trackedConn
+}
+
+func newConnChurn() *connChurn {
+	return &connChurn{conns: make(map[string]*trackedConn)}
+}
+
+// Reason to close the connection after the next request to the host, empty if it's kept
+func (c *connChurn) closeReason(config appConfig, host string) string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Without a connection the request opens a new one
+	tc := c.conns[host]
+	requests := int64(1)
+	if tc != nil {
+		requests += tc.requests.Load()
+	}
+
+	if config.connMaxRequests > 0 && requests >= int64(config.connMaxRequests) {
+		return "max_requests"
+	}
+	if tc != nil && config.connMaxAge > 0 && time.Since(tc.created) >= config.connMaxAge {
+		return "max_age"
+	}
+
+	return ""
+}
+
+// Count the request on the connection it got
+func (c *connChurn) gotConn(host string, conn net.Conn) {
+	tc := getTrackedConn(conn)
+	if tc == nil {
+		return
+	}
+	tc.requests.Add(1)
+
+	c.mutex.Lock()
+	c.conns[host] = tc
+	c.mutex.Unlock()
+}
+
+// Connection to the host is closed after the request, the next request opens a new one.
+// Returns the connection, so it could be closed once it's idle
+func (c *connChurn) closed(config appConfig, host string, reason string) *trackedConn {
+	c.mutex.Lock()
+	tc := c.conns[host]
+	delete(c.conns, host)
+	c.mutex.Unlock()
+
+	if tc != nil {
+		applog.Infof("Closing connection to %v after %v requests and %v, reason: %s", tc.RemoteAddr(), tc.requests.Load(), time.Since(tc.created), reason)
+	}
+
+	localLabelValues := append(config.metrics.labelValues, reason)
+	config.metrics.connectionsRecycled.WithLabelValues(localLabelValues...).Inc()
+
+	return tc
+}
@@ -0,0 +1,641 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Word for unquoted new lines, commands in a file are split by them
+const curlCommandSeparator = "\n"
+
+// Encodings curl --compressed asks for, mapped onto -accept-encoding
+const curlAcceptEncoding = "gzip, deflate, br"
+
+// Request and options parsed from a curl command
+type curlCommand struct {
+	method   string
+	url      string
+	headers  httpHeaders
+	payload  []byte
+	dataFile string
+	form     formFields
+	formArgs []string
+
+	insecure   bool
+	compressed bool
+	timeout    time.Duration
+	proxy      string
+	tlsCA      string
+	tlsCert    string
+	tlsKey     string
+	resolve    []string
+	sendMode   string
+	forceHTTP2 bool
+}
+
+// Curl options we don't need for benchmarking, with and without arguments
+var curlIgnoredOptions = map[string]bool{
+	"-s": false, "--silent": false, "-S": false, "--show-error": false, "-v": false, "--verbose": false,
+	"-i": false, "--include": false, "-L": false, "--location": false, "-f": false, "--fail": false,
+	"-N": false, "--no-buffer": false, "--http1.1": false, "--http2-prior-knowledge": false,
+	"-o": true, "--output": true, "-w": true, "--write-out": true, "--connect-timeout": true,
+	"--retry": true, "--max-redirs": true, "-c": true, "--cookie-jar": true,
+}
+
+// Load curl commands from the argument, or from a file if argument is not a curl command
+func loadCurlCommands(value string) ([]curlCommand, error) {
+	text := strings.TrimSpace(value)
+	if !strings.HasPrefix(text, "curl ") {
+		data, err := os.ReadFile(value)
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+	}
+
+	tokens, err := splitShellWords(text)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every command starts with "curl" on a new line
+	commands := make([]curlCommand, 0)
+	args := make([]string, 0)
+	for i, token := range tokens {
+		if token == "curl" && i > 0 && tokens[i-1] == curlCommandSeparator && len(args) > 0 {
+			command, err := parseCurlCommand(args)
+			if err != nil {
+				return nil, err
+			}
+			commands = append(commands, command)
+			args = make([]string, 0)
+		}
+
+		if token != curlCommandSeparator {
+			args = append(args, token)
+		}
+	}
+
+	if len(args) > 0 {
+		command, err := parseCurlCommand(args)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("no curl commands found")
+	}
+
+	return commands, nil
+}
+
+// Parse curl command arguments, the first one is "curl"
+func parseCurlCommand(args []string) (curlCommand, error) {
+	command := curlCommand{headers: make(httpHeaders)}
+	data := make([]string, 0)
+	var user, cookie string
+	var head, get bool
+
+	if len(args) == 0 || args[0] != "curl" {
+		return command, fmt.Errorf("not a curl command")
+	}
+	args = expandCurlShortOptions(args)
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		// Options with values could be in "--option=value" form
+		name, value, hasValue := arg, "", false
+		if strings.HasPrefix(arg, "--") {
+			name, value, hasValue = strings.Cut(arg, "=")
+		} else if strings.HasPrefix(arg, "-") && len(arg) > 2 && curlOptionHasValue(arg[:2]) {
+			// Short options could have value attached, like -XPOST
+			name, value, hasValue = arg[:2], arg[2:], true
+		}
+
+		nextValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("curl option %s requires a value", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			command.url = arg
+			continue
+		}
+
+		var err error
+		switch name {
+		case "-X", "--request":
+			command.method, err = nextValue()
+		case "--url":
+			command.url, err = nextValue()
+		case "-H", "--header":
+			var header string
+			if header, err = nextValue(); err == nil {
+				key, value, ok := strings.Cut(header, ":")
+				if !ok {
+					return command, fmt.Errorf("wrong curl header %q", header)
+				}
+				command.headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		case "-d", "--data", "--data-ascii", "--data-binary", "--data-raw", "--data-urlencode":
+			var d string
+			if d, err = nextValue(); err == nil {
+				d, err = curlData(name, d, &command)
+				data = append(data, d)
+			}
+		case "-F", "--form":
+			var field string
+			if field, err = nextValue(); err == nil {
+				err = command.form.Set(field)
+				command.formArgs = append(command.formArgs, field)
+			}
+		case "-u", "--user":
+			user, err = nextValue()
+		case "-b", "--cookie":
+			// Curl reads cookies from a file if there's no "="
+			if cookie, err = nextValue(); err == nil && !strings.Contains(cookie, "=") {
+				err = fmt.Errorf("curl cookie files are not supported, use -b 'name=value' or -cookie-jar instead of %q", cookie)
+			}
+		case "-A", "--user-agent":
+			var agent string
+			agent, err = nextValue()
+			command.headers["User-Agent"] = agent
+		case "-e", "--referer":
+			var referer string
+			referer, err = nextValue()
+			command.headers["Referer"] = referer
+		case "-I", "--head":
+			head = true
+		case "-G", "--get":
+			get = true
+		case "-k", "--insecure":
+			command.insecure = true
+		case "--compressed":
+			command.compressed = true
+		case "-m", "--max-time":
+			var seconds string
+			if seconds, err = nextValue(); err == nil {
+				var s float64
+				if s, err = strconv.ParseFloat(seconds, 64); err == nil {
+					command.timeout = time.Duration(s * float64(time.Second))
+				}
+			}
+		case "-x", "--proxy":
+			command.proxy, err = nextValue()
+		case "--cacert":
+			command.tlsCA, err = nextValue()
+		case "-E", "--cert":
+			command.tlsCert, err = nextValue()
+		case "--key":
+			command.tlsKey, err = nextValue()
+		case "--resolve":
+			var resolve string
+			resolve, err = nextValue()
+			command.resolve = append(command.resolve, resolve)
+		case "--http2":
+			command.forceHTTP2 = true
+		case "--http3", "--http3-only":
+			command.sendMode = "http3"
+		default:
+			takesValue, known := curlIgnoredOptions[name]
+			if !known {
+				return command, fmt.Errorf("unsupported curl option %s", name)
+			}
+			if takesValue {
+				_, err = nextValue()
+			}
+		}
+
+		if err != nil {
+			return command, err
+		}
+	}
+
+	if command.url == "" {
+		return command, fmt.Errorf("no URL in curl command")
+	}
+
+	// Curl defaults to http for URLs without scheme
+	if !strings.Contains(command.url, "://") {
+		command.url = "http://" + command.url
+	}
+
+	if len(data) > 0 && len(command.form) > 0 {
+		return command, fmt.Errorf("curl -d and -F options can't be used together")
+	}
+
+	// Body file could be sent as is only if it's the only data
+	if len(data) != 1 {
+		command.dataFile = ""
+	}
+
+	if len(data) > 0 {
+		body := strings.Join(data, "&")
+		if get {
+			separator := "?"
+			if strings.Contains(command.url, "?") {
+				separator = "&"
+			}
+			command.url += separator + body
+		} else {
+			command.payload = []byte(body)
+			if _, ok := curlHeader(command.headers, "Content-Type"); !ok {
+				command.headers["Content-Type"] = "application/x-www-form-urlencoded"
+			}
+		}
+	}
+
+	if user != "" {
+		command.headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(user))
+	}
+
+	if cookie != "" {
+		command.headers["Cookie"] = cookie
+	}
+
+	// Method is implied by options, like curl does
+	if command.method == "" {
+		switch {
+		case head:
+			command.method = "HEAD"
+		case len(command.payload) > 0, len(command.form) > 0:
+			command.method = "POST"
+		default:
+			command.method = "GET"
+		}
+	}
+
+	return command, nil
+}
+
+// Split combined short options, like -sSL. The last one could take a value, like -sXPOST
+func expandCurlShortOptions(args []string) []string {
+	result := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' || curlOptionHasValue(arg[:2]) {
+			result = append(result, arg)
+			continue
+		}
+
+		for i := 1; i < len(arg); i++ {
+			option := "-" + arg[i:i+1]
+			if curlOptionHasValue(option) {
+				result = append(result, option+arg[i+1:])
+				break
+			}
+			result = append(result, option)
+		}
+	}
+
+	return result
+}
+
+// Curl options which take a value
+func curlOptionHasValue(name string) bool {
+	switch name {
+	case "-X", "-H", "-d", "-F", "-u", "-b", "-A", "-e", "-m", "-x", "-E", "-o", "-w", "-c":
+		return true
+	}
+
+	return false
+}
+
+// Get data for -d options, reading files for "@file" values where curl does it
+func curlData(name string, value string, command *curlCommand) (string, error) {
+	switch name {
+	case "--data-raw":
+		return value, nil
+
+	case "--data-urlencode":
+		// "name=content" form encodes the content only
+		if key, content, ok := strings.Cut(value, "="); ok {
+			return key + "=" + url.QueryEscape(content), nil
+		}
+		return url.QueryEscape(value), nil
+	}
+
+	if !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+
+	fileName := strings.TrimPrefix(value, "@")
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	// Only --data-binary keeps new lines
+	if name != "--data-binary" {
+		return strings.NewReplacer("\r", "", "\n", "").Replace(string(data)), nil
+	}
+	command.dataFile = fileName
+
+	return string(data), nil
+}
+
+// Case insensitive header lookup
+func curlHeader(headers httpHeaders, name string) (string, bool) {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// Request sent by curl command
+func (c curlCommand) request() requestSpec {
+	return requestSpec{
+		name:     c.method + " " + c.url,
+		method:   c.method,
+		url:      c.url,
+		headers:  c.headers,
+		payload:  c.payload,
+		form:     c.form,
+		formType: "multipart",
+	}
+}
+
+// Map curl command onto app config, headers from CLI args take precedence
+func (c curlCommand) apply(config *appConfig) {
+	request := c.request()
+	*config = request.apply(*config)
+	c.applyOptions(config)
+}
+
+// Map curl options which are global in minigun onto app config
+func (c curlCommand) applyOptions(config *appConfig) {
+	if c.insecure {
+		config.insecure = true
+	}
+	if c.timeout > 0 {
+		config.sendTimeout = c.timeout
+	}
+	if c.compressed && config.acceptEncoding == "" {
+		config.acceptEncoding = curlAcceptEncoding
+	}
+	if c.proxy != "" {
+		config.proxy = c.proxy
+		if !strings.Contains(config.proxy, "://") {
+			config.proxy = "http://" + config.proxy
+		}
+	}
+	if c.tlsCA != "" {
+		config.tlsCA = c.tlsCA
+	}
+	if c.tlsCert != "" {
+		config.tlsCert = c.tlsCert
+		config.tlsKey = c.tlsCert
+	}
+	if c.tlsKey != "" {
+		config.tlsKey = c.tlsKey
+	}
+	for _, resolve := range c.resolve {
+		if err := config.dnsOverrides.Set(resolve); err != nil {
+			applog.Warningf("Ignoring curl --resolve %q: %s", resolve, err.Error())
+		}
+	}
+	if c.sendMode != "" {
+		config.sendMode = c.sendMode
+	}
+	if c.forceHTTP2 {
+		config.sendForceHTTP2 = true
+	}
+}
+
+// Convert curl commands to a request list
+func curlCommandsToRequests(commands []curlCommand) []requestSpec {
+	requests := make([]requestSpec, 0, len(commands))
+	for _, c := range commands {
+		requests = append(requests, c.request())
+	}
+
+	return requests
+}
+
+// Equivalent minigun command for the curl command
+func (c curlCommand) minigunCommand() string {
+	args := []string{"minigun", "-fire-target", shellQuote(c.url), "-send-method", c.method}
+
+	keys := make([]string, 0, len(c.headers))
+	for key := range c.headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "-http-header", shellQuote(key+": "+c.headers[key]))
+	}
+
+	if len(c.payload) > 0 {
+		if c.dataFile != "" {
+			args = append(args, "-send-file", shellQuote(c.dataFile))
+		} else {
+			// Bash process substitution, so the body doesn't need a file
+			args = append(args, "-send-file", "<(printf '%s' "+shellQuote(string(c.payload))+")")
+		}
+	}
+
+	for _, field := range c.formArgs {
+		args = append(args, "-form", shellQuote(field))
+	}
+
+	if c.insecure {
+		args = append(args, "-insecure")
+	}
+	if c.timeout > 0 {
+		args = append(args, "-send-timeout", c.timeout.String())
+	}
+	if c.compressed {
+		args = append(args, "-accept-encoding", shellQuote(curlAcceptEncoding))
+	}
+	if c.proxy != "" {
+		args = append(args, "-proxy", shellQuote(c.proxy))
+	}
+	if c.tlsCA != "" {
+		args = append(args, "-tls-ca", shellQuote(c.tlsCA))
+	}
+	if c.tlsCert != "" {
+		args = append(args, "-tls-cert", shellQuote(c.tlsCert))
+	}
+	if c.tlsKey != "" {
+		args = append(args, "-tls-key", shellQuote(c.tlsKey))
+	}
+	for _, resolve := range c.resolve {
+		args = append(args, "-resolve", shellQuote(resolve))
+	}
+	if c.sendMode != "" {
+		args = append(args, "-send-mode", c.sendMode)
+	}
+	if c.forceHTTP2 {
+		args = append(args, "-force-attempt-http2")
+	}
+
+	return strings.Join(args, " ")
+}
+
+// Quote string for POSIX shell if needed
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@,+%") == "" {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Split shell command line into words, supporting quotes, escapes and line continuations.
+// Unquoted new lines are returned as separator words, so multiple commands could be split
+func splitShellWords(text string) ([]string, error) {
+	words := make([]string, 0)
+	var word strings.Builder
+	inWord := false
+
+	flush := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+
+		switch {
+		case ch == '\\' && i+1 < len(text):
+			i++
+			// Line continuation, Windows line endings too
+			if text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+				i++
+				continue
+			}
+			if text[i] == '\n' {
+				continue
+			}
+			word.WriteByte(text[i])
+			inWord = true
+
+		case ch == '\n':
+			flush()
+			words = append(words, curlCommandSeparator)
+
+		case ch == ' ' || ch == '\t' || ch == '\r':
+			flush()
+
+		case ch == '#' && !inWord:
+			// Comment until the end of line
+			for i+1 < len(text) && text[i+1] != '\n' {
+				i++
+			}
+
+		case ch == '\'':
+			end := strings.IndexByte(text[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(text[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+
+		case ch == '$' && i+1 < len(text) && text[i+1] == '\'':
+			n, err := readANSICQuoted(text[i+2:], &word)
+			if err != nil {
+				return nil, err
+			}
+			i += n + 2
+			inWord = true
+
+		case ch == '"':
+			i++
+			for ; i < len(text) && text[i] != '"'; i++ {
+				if text[i] == '\\' && i+1 < len(text) && strings.IndexByte("\"\\$`\n", text[i+1]) >= 0 {
+					i++
+					if text[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(text[i])
+			}
+			if i >= len(text) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+
+		default:
+			word.WriteByte(ch)
+			inWord = true
+		}
+	}
+	flush()
+
+	return words, nil
+}
+
+// Read bash $'...' string until the closing quote, returns number of bytes read including the quote
+func readANSICQuoted(text string, word *strings.Builder) (int, error) {
+	escapes := map[byte]string{'n': "\n", 't': "\t", 'r': "\r", '\\': "\\", '\'': "'", '"': "\"", 'a': "\a", 'b': "\b", 'e': "\x1b", 'f': "\f", 'v': "\v", '?': "?"}
+
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		if ch == '\'' {
+			return i + 1, nil
+		}
+		if ch != '\\' || i+1 >= len(text) {
+			word.WriteByte(ch)
+			continue
+		}
+
+		i++
+		if escaped, ok := escapes[text[i]]; ok {
+			word.WriteString(escaped)
+			continue
+		}
+
+		// Hex and unicode escapes, like \x41 and \u00e9
+		digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[text[i]]
+		if digits == 0 {
+			word.WriteByte('\\')
+			word.WriteByte(text[i])
+			continue
+		}
+
+		end := i + 1
+		for end < len(text) && end < i+1+digits && strings.IndexByte("0123456789abcdefABCDEF", text[end]) >= 0 {
+			end++
+		}
+		code, err := strconv.ParseUint(text[i+1:end], 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("wrong escape sequence in $'' string")
+		}
+		if text[i] == 'x' {
+			word.WriteByte(byte(code))
+		} else {
+			word.WriteString(string(utf8.AppendRune(nil, rune(code))))
+		}
+		i = end - 1
+	}
+
+	return 0, fmt.Errorf("unterminated $'' string")
+}
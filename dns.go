@@ -0,0 +1,251 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Custom type to parse curl style "host:port:addr[,addr]..." DNS overrides as multiple flag cli args
+type dnsOverrides map[string][]string
+
+func (overrides *dnsOverrides) String() string {
+	return "DNS overrides"
+}
+
+func (overrides *dnsOverrides) Set(value string) error {
+	s := strings.SplitN(value, ":", 3)
+	if len(s) < 3 || s[0] == "" || s[1] == "" || s[2] == "" {
+		return fmt.Errorf("wrong resolve argument, expected 'host:port:addr[,addr]...'")
+	}
+
+	addrs := make([]string, 0)
+	for _, addr := range strings.Split(s[2], ",") {
+		addr = strings.Trim(strings.TrimSpace(addr), "[]")
+		if net.ParseIP(addr) == nil {
+			return fmt.Errorf("wrong IP address %q in resolve argument", addr)
+		}
+		addrs = append(addrs, addr)
+	}
+
+	if *overrides == nil {
+		*overrides = make(dnsOverrides)
+	}
+	(*overrides)[net.JoinHostPort(s[0], s[1])] = addrs
+
+	return nil
+}
+
+// Cached DNS lookup result
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// Resolver shared by all workers
+type dnsResolver struct {
+	resolver   *net.Resolver
+	overrides  dnsOverrides
+	roundRobin bool
+	cacheTTL   time.Duration
+
+	next  atomic.Uint64
+	mutex sync.Mutex
+	cache map[string]dnsCacheEntry
+}
+
+// Init resolver, returns nil if default Go resolver behaviour is good enough
+func initDNSResolver(config appConfig) (*dnsResolver, error) {
+	if len(config.dnsOverrides) == 0 && config.dnsServer == "" && !config.dnsRoundRobin && config.dnsCacheTTL == 0 {
+		return nil, nil
+	}
+
+	r := &dnsResolver{
+		resolver:   net.DefaultResolver,
+		overrides:  config.dnsOverrides,
+		roundRobin: config.dnsRoundRobin,
+		cacheTTL:   config.dnsCacheTTL,
+		cache:      make(map[string]dnsCacheEntry),
+	}
+
+	if config.dnsServer != "" {
+		server := config.dnsServer
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			return nil, fmt.Errorf("wrong DNS server address %q: %s", config.dnsServer, err.Error())
+		}
+
+		r.resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				d := net.Dialer{Timeout: config.sendTimeout}
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	return r, nil
+}
+
+// Resolve "host:port" address into a list of "ip:port" addresses, in the order they should be tried
+func (r *dnsResolver) resolve(ctx context.Context, address string) ([]string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []string
+
+	if net.ParseIP(host) != nil {
+		ips = []string{host}
+	} else if ips, err = r.lookupTraced(ctx, address, host); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(ips))
+	offset := 0
+	if r.roundRobin {
+		offset = int((r.next.Add(1) - 1) % uint64(len(ips)))
+	}
+	for i := range ips {
+		result[i] = net.JoinHostPort(ips[(i+offset)%len(ips)], port)
+	}
+
+	return result, nil
+}
+
+// Lookup host name with overrides, calling DNS httptrace hooks of the request like the default dialer does.
+// net.Resolver.LookupHost doesn't call them, and cached or overridden names should be reported too
+func (r *dnsResolver) lookupTraced(ctx context.Context, address string, host string) ([]string, error) {
+	trace := httptrace.ContextClientTrace(ctx)
+	if trace != nil && trace.DNSStart != nil {
+		trace.DNSStart(httptrace.DNSStartInfo{Host: host})
+	}
+
+	addrs, ok := r.overrides[address]
+	var err error
+	if !ok {
+		addrs, err = r.lookup(ctx, host)
+	}
+
+	if trace != nil && trace.DNSDone != nil {
+		info := httptrace.DNSDoneInfo{Err: err}
+		for _, addr := range addrs {
+			info.Addrs = append(info.Addrs, net.IPAddr{IP: net.ParseIP(addr)})
+		}
+		trace.DNSDone(info)
+	}
+
+	return addrs, err
+}
+
+// Lookup host, using cache if enabled
+func (r *dnsResolver) lookup(ctx context.Context, host string) ([]string, error) {
+	if r.cacheTTL > 0 {
+		r.mutex.Lock()
+		entry, ok := r.cache[host]
+		r.mutex.Unlock()
+
+		if ok && time.Now().Before(entry.expires) {
+			return entry.addrs, nil
+		}
+	}
+
+	addrs, err := r.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.cacheTTL > 0 {
+		r.mutex.Lock()
+		r.cache[host] = dnsCacheEntry{addrs: addrs, expires: time.Now().Add(r.cacheTTL)}
+		r.mutex.Unlock()
+	}
+
+	return addrs, nil
+}
+
+// Do we need our own dial functions instead of transport defaults
+func useCustomDial(config appConfig) bool {
+	return config.dnsResolver != nil || config.sourceIP != "" || useConnChurn(config)
+}
+
+// Dial function for HTTP transports which resolves addresses with our resolver and binds to source IP
+func initDialContext(config appConfig) func(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: config.sendTimeout, KeepAlive: 30 * time.Second, LocalAddr: sourceTCPAddr(config)}
+	dial := dialer.DialContext
+
+	if config.dnsResolver != nil {
+		dial = initResolvingDialContext(config, dialer)
+	}
+
+	if useConnChurn(config) {
+		dial = withConnChurn(config, dial)
+	}
+
+	return dial
+}
+
+// Dial function which tries all addresses from our resolver
+func initResolvingDialContext(config appConfig, dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		addrs, err := config.dnsResolver.resolve(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+
+		// Try all addresses until one works, like net.Dialer does
+		for _, addr := range addrs {
+			var conn net.Conn
+			if conn, err = dialer.DialContext(ctx, network, addr); err == nil {
+				return conn, nil
+			}
+		}
+
+		return nil, err
+	}
+}
+
+// TLS dial function for HTTP/2 transport
+func initDialTLSContext(config appConfig) func(ctx context.Context, network, address string, cfg *tls.Config) (net.Conn, error) {
+	dialContext := initDialContext(config)
+
+	return func(ctx context.Context, network, address string, cfg *tls.Config) (net.Conn, error) {
+		conn, err := dialContext(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		trace := httptrace.ContextClientTrace(ctx)
+		if trace != nil && trace.TLSHandshakeStart != nil {
+			trace.TLSHandshakeStart()
+		}
+
+		tlsConn := tls.Client(conn, cfg)
+		err = tlsConn.HandshakeContext(ctx)
+
+		if trace != nil && trace.TLSHandshakeDone != nil {
+			trace.TLSHandshakeDone(tlsConn.ConnectionState(), err)
+		}
+
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return tlsConn, nil
+	}
+}
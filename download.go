@@ -0,0 +1,127 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Server-sent events parser state, events are separated by empty lines and comment lines are not events
+type sseParser struct {
+	atLineStart bool
+	hasField    bool
+	events      int
+	lastEvent   time.Time
+}
+
+// Parse next part of the stream, returns the number of complete events in it.
+// Events after -sse-max-events are not dispatched, even when they arrive in the same read
+func (p *sseParser) parse(config appConfig, data []byte) int {
+	events := 0
+
+	for _, c := range data {
+		switch {
+		case c == '\r':
+			continue
+		case c == '\n':
+			// Empty line ends the event
+			if p.atLineStart {
+				if p.hasField {
+					p.dispatch(config)
+					events++
+					if config.sseMaxEvents > 0 && p.events >= config.sseMaxEvents {
+						return events
+					}
+				}
+				p.hasField = false
+			}
+			p.atLineStart = true
+		case p.atLineStart:
+			p.atLineStart = false
+			p.hasField = p.hasField || c != ':'
+		}
+	}
+
+	return events
+}
+
+// Count the event and observe the time since the previous one
+func (p *sseParser) dispatch(config appConfig) {
+	now := time.Now()
+	if !p.lastEvent.IsZero() {
+		config.metrics.histSSEInterEventLatency.WithLabelValues(config.metrics.labelValues...).Observe(now.Sub(p.lastEvent).Seconds())
+		config.metrics.summarySSEInterEventLatency.WithLabelValues(config.metrics.labelValues...).Observe(now.Sub(p.lastEvent).Seconds())
+	}
+	config.metrics.sseEvents.WithLabelValues(config.metrics.labelValues...).Inc()
+
+	p.lastEvent = now
+	p.events++
+}
+
+// Read response body in chunks without buffering it, with time to last byte and throughput per response.
+// Reading stops at EOF, after -read-max-duration or after -sse-max-events, the last two are not errors
+func readResponseStream(config appConfig, resp *http.Response, start time.Time) (int64, error) {
+	var stopped atomic.Bool
+	var bytes int64
+	var last time.Time
+
+	received := time.Now()
+	if config.readMaxDuration > 0 {
+		timer := time.AfterFunc(config.readMaxDuration, func() {
+			stopped.Store(true)
+			resp.Body.Close()
+		})
+		defer timer.Stop()
+	}
+
+	var sse *sseParser
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && mediaType == "text/event-stream" {
+		sse = &sseParser{atLineStart: true}
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			bytes += int64(n)
+			last = time.Now()
+
+			if sse != nil && sse.parse(config, buf[:n]) > 0 && config.sseMaxEvents > 0 && sse.events >= config.sseMaxEvents {
+				stopped.Store(true)
+				break
+			}
+		}
+
+		if err == io.EOF || (err != nil && stopped.Load()) {
+			break
+		}
+		if err != nil {
+			return bytes, err
+		}
+	}
+
+	if !last.IsZero() {
+		config.metrics.histTimeToLastByte.WithLabelValues(config.metrics.labelValues...).Observe(last.Sub(start).Seconds())
+		config.metrics.summaryTimeToLastByte.WithLabelValues(config.metrics.labelValues...).Observe(last.Sub(start).Seconds())
+
+		if seconds := last.Sub(received).Seconds(); seconds > 0 {
+			config.metrics.histDownloadThroughput.WithLabelValues(config.metrics.labelValues...).Observe(float64(bytes) / seconds)
+			config.metrics.summaryDownloadThroughput.WithLabelValues(config.metrics.labelValues...).Observe(float64(bytes) / seconds)
+		}
+	}
+
+	if sse != nil {
+		applog.Infof("Read %v bytes and %v events in %v", bytes, sse.events, time.Since(received))
+	} else {
+		applog.Infof("Read %v bytes in %v", bytes, time.Since(received))
+	}
+
+	return bytes, nil
+}
@@ -0,0 +1,162 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content encodings we can decode
+var supportedContentEncodings = []string{"gzip", "deflate", "br", "zstd"}
+
+// Content encodings we can compress request bodies with
+var supportedSendCompressions = []string{"gzip", "deflate", "zstd"}
+
+// zstd decoder and encoder are safe for concurrent use, and they're expensive to create per request
+var zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// Validate comma separated -accept-encoding list
+func validateAcceptEncoding(value string) error {
+	for _, encoding := range strings.Split(value, ",") {
+		// Quality values are allowed, like "br;q=1.0, gzip;q=0.5"
+		name, _, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		name = strings.TrimSpace(name)
+		if name == "identity" || name == "*" {
+			continue
+		}
+
+		supported := false
+		for _, s := range supportedContentEncodings {
+			supported = supported || s == name
+		}
+		if !supported {
+			return fmt.Errorf("unsupported encoding %q, supported are %s", name, strings.Join(supportedContentEncodings, ", "))
+		}
+	}
+
+	return nil
+}
+
+// Response content encoding for metrics, transports don't decode responses so it's what was on the wire
+func responseEncoding(resp *http.Response) string {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" {
+		return "identity"
+	}
+
+	return encoding
+}
+
+// Decode response body, encodings are listed in the order they were applied
+func decodeBody(body []byte, contentEncoding string) ([]byte, error) {
+	encodings := strings.Split(contentEncoding, ",")
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var reader io.Reader
+		var err error
+
+		switch encoding := strings.TrimSpace(encodings[i]); encoding {
+		case "identity", "":
+			continue
+		case "gzip", "x-gzip":
+			reader, err = gzip.NewReader(bytes.NewReader(body))
+		case "deflate":
+			// Deflate should be zlib wrapped, but some servers send raw deflate
+			if reader, err = zlib.NewReader(bytes.NewReader(body)); err != nil {
+				reader, err = flate.NewReader(bytes.NewReader(body)), nil
+			}
+		case "br":
+			reader = brotli.NewReader(bytes.NewReader(body))
+		case "zstd":
+			if body, err = zstdDecoder.DecodeAll(body, nil); err != nil {
+				return nil, fmt.Errorf("error decoding zstd response: %s", err.Error())
+			}
+			continue
+		default:
+			return nil, fmt.Errorf("unsupported response content encoding %q", encoding)
+		}
+
+		if err == nil {
+			body, err = io.ReadAll(reader)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %s response: %s", strings.TrimSpace(encodings[i]), err.Error())
+		}
+	}
+
+	return body, nil
+}
+
+// Responses to HEAD requests and 204, 304 responses have no body, even with Content-Encoding header
+func responseHasBody(resp *http.Response, body []byte) bool {
+	if len(body) == 0 || resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified {
+		return false
+	}
+
+	return resp.Request == nil || resp.Request.Method != http.MethodHead
+}
+
+// Count wire and decoded bytes, decode the body if asked, with decompression time tracked separately
+func observeResponseBody(config appConfig, resp *http.Response, body []byte) ([]byte, error) {
+	encoding := responseEncoding(resp)
+	wireBytes := len(body)
+
+	if config.decompress && encoding != "identity" && responseHasBody(resp, body) {
+		started := time.Now()
+		decoded, err := decodeBody(body, encoding)
+		if err != nil {
+			return nil, err
+		}
+		config.metrics.histDecompressDuration.WithLabelValues(config.metrics.labelValues...).Observe(time.Since(started).Seconds())
+		config.metrics.summaryDecompressDuration.WithLabelValues(config.metrics.labelValues...).Observe(time.Since(started).Seconds())
+		body = decoded
+	}
+
+	localLabelValues := append(config.metrics.labelValues, encoding)
+	config.metrics.responsesByEncoding.WithLabelValues(localLabelValues...).Inc()
+	config.metrics.responseBytesSum.WithLabelValues(config.metrics.labelValues...).Add(float64(wireBytes))
+	config.metrics.responseDecodedBytesSum.WithLabelValues(config.metrics.labelValues...).Add(float64(len(body)))
+
+	return body, nil
+}
+
+// Compress request body, deflate is zlib wrapped as HTTP expects
+func compressBody(data []byte, encoding string) ([]byte, error) {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+
+	switch encoding {
+	case "gzip":
+		writer = gzip.NewWriter(&buf)
+	case "deflate":
+		writer = zlib.NewWriter(&buf)
+	case "zstd":
+		return zstdEncoder.EncodeAll(data, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %q, supported are %s", encoding, strings.Join(supportedSendCompressions, ", "))
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,155 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Form field, file parts keep file contents loaded at startup
+type formField struct {
+	name        string
+	value       string
+	file        bool
+	fileName    string
+	contentType string
+}
+
+// Custom type to parse curl -F style form fields as multiple flag cli args
+type formFields []formField
+
+var formQuoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func (fields *formFields) String() string {
+	return "Form fields"
+}
+
+// Parse "name=value", "name=<file" for a text field from file, or "name=@file;type=mime;filename=name" for a file part
+func (fields *formFields) Set(value string) error {
+	name, content, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("wrong form field %q, expected 'name=value', 'name=<file' or 'name=@file'", value)
+	}
+
+	field := formField{name: name, value: content}
+
+	switch {
+	case strings.HasPrefix(content, "@"):
+		options := strings.Split(content[1:], ";")
+		path := options[0]
+		field.file, field.fileName = true, filepath.Base(path)
+
+		for _, option := range options[1:] {
+			key, optionValue, _ := strings.Cut(option, "=")
+			switch strings.TrimSpace(key) {
+			case "type":
+				field.contentType = optionValue
+			case "filename":
+				field.fileName = optionValue
+			default:
+				return fmt.Errorf("unsupported form file option %q", option)
+			}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		field.value = string(data)
+
+	case strings.HasPrefix(content, "<"):
+		data, err := os.ReadFile(content[1:])
+		if err != nil {
+			return err
+		}
+		field.value = string(data)
+	}
+
+	*fields = append(*fields, field)
+
+	return nil
+}
+
+// Build form body with its Content-Type. Multipart bodies get a new random boundary every time, from the worker
+// random generator if there is one, so seeded runs send the same bodies
+func buildFormBody(fields formFields, formType string, rng *rand.Rand) ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	switch formType {
+	case "urlencoded":
+		for i, field := range fields {
+			if field.file {
+				return nil, "", fmt.Errorf("file part %q can't be sent in urlencoded form", field.name)
+			}
+			if i > 0 {
+				buf.WriteByte('&')
+			}
+			buf.WriteString(url.QueryEscape(field.name) + "=" + url.QueryEscape(field.value))
+		}
+		return buf.Bytes(), "application/x-www-form-urlencoded", nil
+
+	case "multipart":
+		writer := multipart.NewWriter(&buf)
+		if rng != nil {
+			// Same length as the default boundary
+			boundary := make([]byte, 30)
+			rng.Read(boundary)
+			if err := writer.SetBoundary(fmt.Sprintf("%x", boundary)); err != nil {
+				return nil, "", err
+			}
+		}
+		for _, field := range fields {
+			if !field.file {
+				if err := writer.WriteField(field.name, field.value); err != nil {
+					return nil, "", err
+				}
+				continue
+			}
+
+			contentType := field.contentType
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+
+			header := make(textproto.MIMEHeader)
+			header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, formQuoteEscaper.Replace(field.name), formQuoteEscaper.Replace(field.fileName)))
+			header.Set("Content-Type", contentType)
+
+			part, err := writer.CreatePart(header)
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err := part.Write([]byte(field.value)); err != nil {
+				return nil, "", err
+			}
+		}
+
+		if err := writer.Close(); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), writer.FormDataContentType(), nil
+	}
+
+	return nil, "", fmt.Errorf("unsupported form type %q, supported are multipart and urlencoded", formType)
+}
+
+// Size of the form body, it's the same for every request since multipart boundaries have fixed length
+func formBodySize(config appConfig) int64 {
+	body, _, err := buildFormBody(config.form, config.formType, nil)
+	if err != nil {
+		return 0
+	}
+
+	return int64(len(body))
+}
@@ -1,15 +1,20 @@
 module github.com/wayfair-incubator/minigun
 
-go 1.26
+go 1.26.0
 
 require (
+	github.com/andybalholm/brotli v1.2.0
 	github.com/dustin/go-humanize v1.0.1
 	github.com/google/logger v1.1.2
 	github.com/gorilla/mux v1.8.1
+	github.com/klauspost/compress v1.18.0
 	github.com/olekukonko/tablewriter v0.0.5
 	github.com/prometheus/client_golang v1.23.2
 	github.com/prometheus/client_model v0.6.2
-	golang.org/x/net v0.53.0
+	github.com/quic-go/quic-go v0.63.0
+	go.yaml.in/yaml/v3 v3.0.5
+	golang.org/x/net v0.56.0
+	google.golang.org/protobuf v1.36.8
 )
 
 require (
@@ -20,8 +25,9 @@ require (
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/prometheus/common v0.66.1 // indirect
 	github.com/prometheus/procfs v0.16.1 // indirect
+	github.com/quic-go/qpack v0.6.0 // indirect
 	go.yaml.in/yaml/v2 v2.4.2 // indirect
-	golang.org/x/sys v0.43.0 // indirect
-	golang.org/x/text v0.36.0 // indirect
-	google.golang.org/protobuf v1.36.8 // indirect
+	golang.org/x/crypto v0.54.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
 )
@@ -0,0 +1,209 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HAR file structure, only fields we need for replay. See http://www.softwareishard.com/blog/har-12-spec/
+type harFile struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time  `json:"startedDateTime"`
+	Request         harRequest `json:"request"`
+}
+
+type harRequest struct {
+	Method   string         `json:"method"`
+	URL      string         `json:"url"`
+	Headers  []harNameValue `json:"headers"`
+	PostData *harPostData   `json:"postData"`
+}
+
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Params   []harNameValue `json:"params"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Headers which are set by HTTP client and should not be replayed as is
+var harSkipHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Connection":        true,
+	"Keep-Alive":        true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+}
+
+// Load HAR file into a request list, filtered by entry indexes and hosts
+func loadHAR(fileName string, entries string, hosts string) ([]requestSpec, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("error parsing HAR file: %s", err.Error())
+	}
+
+	indexes, err := parseIndexRanges(entries)
+	if err != nil {
+		return nil, fmt.Errorf("wrong HAR entries filter: %s", err.Error())
+	}
+
+	hostFilter := make(map[string]bool)
+	for _, host := range strings.Split(hosts, ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			hostFilter[host] = true
+		}
+	}
+
+	filtered := make([]harEntry, 0)
+	for i, entry := range har.Log.Entries {
+		if len(indexes) > 0 && !indexes.contains(i) {
+			continue
+		}
+
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("wrong URL in HAR entry %v: %s", i, err.Error())
+		}
+		if len(hostFilter) > 0 && !hostFilter[strings.ToLower(u.Hostname())] {
+			continue
+		}
+
+		filtered = append(filtered, entry)
+	}
+
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no HAR entries to replay")
+	}
+
+	// Browsers don't always write entries in order
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].StartedDateTime.Before(filtered[j].StartedDateTime)
+	})
+
+	requests := make([]requestSpec, 0, len(filtered))
+	for _, entry := range filtered {
+		request := harEntryToRequest(entry.Request)
+		if !entry.StartedDateTime.IsZero() && !filtered[0].StartedDateTime.IsZero() {
+			request.offset = entry.StartedDateTime.Sub(filtered[0].StartedDateTime)
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// Convert HAR request to our request
+func harEntryToRequest(r harRequest) requestSpec {
+	request := requestSpec{
+		name:    r.URL,
+		method:  strings.ToUpper(r.Method),
+		url:     r.URL,
+		headers: make(httpHeaders),
+	}
+
+	for _, header := range r.Headers {
+		// Skip HTTP/2 pseudo headers, like ":authority"
+		key := http.CanonicalHeaderKey(header.Name)
+		if strings.HasPrefix(header.Name, ":") || harSkipHeaders[key] {
+			continue
+		}
+
+		// HTTP/2 sessions could have multiple cookie headers
+		if value, ok := request.headers[key]; ok {
+			separator := ", "
+			if key == "Cookie" {
+				separator = "; "
+			}
+			request.headers[key] = value + separator + header.Value
+		} else {
+			request.headers[key] = header.Value
+		}
+	}
+
+	if r.PostData != nil {
+		if r.PostData.Text != "" {
+			request.payload = []byte(r.PostData.Text)
+		} else if len(r.PostData.Params) > 0 {
+			form := url.Values{}
+			for _, param := range r.PostData.Params {
+				form.Add(param.Name, param.Value)
+			}
+			request.payload = []byte(form.Encode())
+		}
+
+		if _, ok := request.headers["Content-Type"]; !ok && r.PostData.MimeType != "" {
+			request.headers["Content-Type"] = r.PostData.MimeType
+		}
+	}
+
+	return request
+}
+
+// Inclusive index ranges, kept as bounds so huge ranges don't take memory
+type indexRanges [][2]int
+
+func (r indexRanges) contains(index int) bool {
+	for _, bounds := range r {
+		if index >= bounds[0] && index <= bounds[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse comma separated list of indexes and ranges, like "0-5,8"
+func parseIndexRanges(value string) (indexRanges, error) {
+	var result indexRanges
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(item, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("wrong index %q", item)
+		}
+
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(strings.TrimSpace(to)); err != nil || last < first {
+				return nil, fmt.Errorf("wrong range %q", item)
+			}
+		}
+
+		result = append(result, [2]int{first, last})
+	}
+
+	return result, nil
+}
@@ -33,10 +33,20 @@ is in total 10 kB/s across all concurrent requests during benchmark duration.
 	outMatrix = append(outMatrix, printRow{"Full request duration", "Full time of a request starting from the beginning (DNS lookup) and ending with receiving a full response."})
 	outMatrix = append(outMatrix, printRow{"DNS request duration", "The time spent on DNS lookup."})
 	outMatrix = append(outMatrix, printRow{"TCP connection duration", "The time spent on establishing TCP connection using a TCP handshake."})
-	outMatrix = append(outMatrix, printRow{"TLS handshake duration", "The time spent on TLS handshake."})
+	outMatrix = append(outMatrix, printRow{"Connection idle time", "The time a reused connection was idle in the connection pool before it was picked for the request."})
+	outMatrix = append(outMatrix, printRow{"Proxy CONNECT duration", "The time since CONNECT request is sent to the proxy and until the proxy replies with the established tunnel. Measured for HTTPS targets and HTTP proxies only."})
+	outMatrix = append(outMatrix, printRow{"TLS handshake duration", "The time spent on TLS handshake. For http3 send mode it's the full QUIC handshake, which includes TLS."})
+	outMatrix = append(outMatrix, printRow{"TLS full handshake", "The time spent on TLS handshakes which were not resumed from a previous TLS session."})
+	outMatrix = append(outMatrix, printRow{"TLS resumed handshake", "The time spent on TLS handshakes resumed from a previous TLS session, using session tickets."})
 	outMatrix = append(outMatrix, printRow{"HTTP write request body", "The time required to write request body to the remote endpoint."})
 	outMatrix = append(outMatrix, printRow{"HTTP time to first byte", "The time since the request start and when the first byte of HTTP reply from the remote endpoint is received. This time includes DNS lookup, establishing the TCP connection and SSL handshake if the request is made over https."})
 	outMatrix = append(outMatrix, printRow{"HTTP response duration", "The time since request headers and body are sent and until the full response is received."})
+	outMatrix = append(outMatrix, printRow{"HTTP time to last byte", "The time since the request start and until the last byte of the response is read. Measured with -read-stream only."})
+	outMatrix = append(outMatrix, printRow{"SSE inter-event latency", "The time between consecutive server-sent events of the same response. Measured with -read-stream only."})
+	outMatrix = append(outMatrix, printRow{"Response decompression", "The time spent on decoding compressed responses, after the full response is received. Not measured with -decompress=false."})
+	outMatrix = append(outMatrix, printRow{"Redirect hop", "The time of every hop of redirected requests, since the request of the hop is sent and until its response headers are received."})
+	outMatrix = append(outMatrix, printRow{"Auth token fetch", "The time spent on getting a new auth token from OAuth2 token endpoint or on signing a new JWT. It's not a part of benchmarked requests."})
+	outMatrix = append(outMatrix, printRow{"Replay schedule lag", "The delay between the time a recorded request should be replayed at and the time it's actually handed to workers."})
 
 	report += "\n\n" + formatPrintMatrix(outHeader, outMatrix, true, false)
 
@@ -0,0 +1,155 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+)
+
+// Init HTTP/3 transport
+func initHTTP3Transport(config appConfig, tlsConfig *tls.Config) *http3.Transport {
+
+	// Session tickets are required for 0-RTT resumption
+	if config.http3ZeroRTT && tlsConfig.ClientSessionCache == nil {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+
+	return &http3.Transport{
+		TLSClientConfig:    tlsConfig,
+		DisableCompression: true,
+		QUICConfig:         &quic.Config{HandshakeIdleTimeout: config.sendTimeout},
+		Dial: func(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (*quic.Conn, error) {
+			return dialQUIC(ctx, addr, tlsCfg, cfg, config)
+		},
+	}
+}
+
+// Dial QUIC connection and observe handshake duration
+func dialQUIC(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config, config appConfig) (*quic.Conn, error) {
+	start := time.Now()
+
+	addrs := []string{addr}
+	if config.dnsResolver != nil {
+		var err error
+		if addrs, err = config.dnsResolver.resolve(ctx, addr); err != nil {
+			return nil, err
+		}
+	}
+
+	// Try all addresses until one works, like TCP dial does
+	var conn *quic.Conn
+	var err error
+	for _, addr := range addrs {
+		if conn, err = dialQUICEarly(ctx, addr, tlsCfg, cfg, config); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Handshake is observed in background when 0-RTT is enabled, so the first request could be sent early
+	if config.http3ZeroRTT {
+		go observeQUICHandshake(conn, start, config)
+		return conn, nil
+	}
+
+	select {
+	case <-conn.HandshakeComplete():
+		observeQUICHandshake(conn, start, config)
+	case <-ctx.Done():
+		conn.CloseWithError(0, "")
+		return nil, ctx.Err()
+	}
+
+	return conn, nil
+}
+
+// Dial QUIC connection from the source IP, if configured
+func dialQUICEarly(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config, config appConfig) (*quic.Conn, error) {
+	if config.sourceIP == "" {
+		return quic.DialAddrEarly(ctx, addr, tlsCfg, cfg)
+	}
+
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP(config.sourceIP)})
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := quic.DialEarly(ctx, udpConn, udpAddr, tlsCfg, cfg)
+	if err != nil {
+		udpConn.Close()
+		return nil, err
+	}
+
+	// UDP socket is ours, so we close it when QUIC connection is closed
+	go func() {
+		<-conn.Context().Done()
+		udpConn.Close()
+	}()
+
+	return conn, nil
+}
+
+// QUIC includes TLS 1.3 handshake, so we report it as TLS handshake to keep reports and dashboards the same
+func observeQUICHandshake(conn *quic.Conn, start time.Time, config appConfig) {
+	select {
+	case <-conn.HandshakeComplete():
+	case <-conn.Context().Done():
+		return
+	}
+
+	handshake := time.Since(start)
+	config.metrics.histTLSHandshakeDuration.WithLabelValues(config.metrics.labelValues...).Observe(handshake.Seconds())
+	config.metrics.summaryTLSHandshakeDuration.WithLabelValues(config.metrics.labelValues...).Observe(handshake.Seconds())
+	applog.Infof("QUIC Handshake: %v\n", handshake)
+	observeTLSConnectionState(config, conn.ConnectionState().TLS, handshake)
+
+	if conn.ConnectionState().Used0RTT {
+		config.metrics.quicZeroRTTConnections.WithLabelValues(config.metrics.labelValues...).Inc()
+		applog.Info("QUIC 0-RTT accepted")
+	}
+}
+
+// Use 0-RTT request methods for idempotent requests when enabled
+func http3RequestMethod(config appConfig) string {
+	if config.sendMode != "http3" || !config.http3ZeroRTT {
+		return config.sendMethod
+	}
+
+	switch config.sendMethod {
+	case http.MethodGet:
+		return http3.MethodGet0RTT
+	case http.MethodHead:
+		return http3.MethodHead0RTT
+	}
+
+	return config.sendMethod
+}
+
+// Method the server gets, 0-RTT request methods are sent as GET and HEAD
+func http3WireMethod(method string) string {
+	switch method {
+	case http3.MethodGet0RTT:
+		return http.MethodGet
+	case http3.MethodHead0RTT:
+		return http.MethodHead
+	}
+
+	return method
+}
@@ -7,6 +7,8 @@ package main
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -20,18 +22,43 @@ type appMetrics struct {
 	labelValues []string
 
 	// Counters
-	channelFullEvents    *prometheus.CounterVec
-	requestsSendCount    *prometheus.CounterVec
-	requestsSendBytesSum *prometheus.CounterVec
-	requestsSendSuccess  *prometheus.CounterVec
-	requestsSendErrors   *prometheus.CounterVec
-	responseBytesCount   *prometheus.CounterVec
-	responseBytesSum     *prometheus.CounterVec
+	channelFullEvents       *prometheus.CounterVec
+	requestsSendCount       *prometheus.CounterVec
+	requestsSendBytesSum    *prometheus.CounterVec
+	requestsSendRawBytesSum *prometheus.CounterVec
+	requestsSendSuccess     *prometheus.CounterVec
+	requestsSendErrors      *prometheus.CounterVec
+	responseBytesCount      *prometheus.CounterVec
+	responseBytesSum        *prometheus.CounterVec
+	responseDecodedBytesSum *prometheus.CounterVec
+	responsesByEncoding     *prometheus.CounterVec
+	requestsByAddress       *prometheus.CounterVec
+	requestsBySourceIP      *prometheus.CounterVec
+	errorsBySourceIP        *prometheus.CounterVec
+	connectionsGot          *prometheus.CounterVec
+	connectionsRecycled     *prometheus.CounterVec
+	requestsByName          *prometheus.CounterVec
+	requestsByTarget        *prometheus.CounterVec
+	errorsByTarget          *prometheus.CounterVec
+	errorsByName            *prometheus.CounterVec
+	authTokenFetches        *prometheus.CounterVec
+	authTokenFetchErrors    *prometheus.CounterVec
+
+	sessionExtractedVariables  *prometheus.CounterVec
+	sessionUnresolvedVariables *prometheus.CounterVec
+
+	redirects             *prometheus.CounterVec
+	sseEvents             *prometheus.CounterVec
+	requestsByFirstStatus *prometheus.CounterVec
+
+	quicZeroRTTConnections *prometheus.CounterVec
+	tlsConnections         *prometheus.CounterVec
 
 	// Gauges
-	configWorkers       *prometheus.GaugeVec
-	channelLength       *prometheus.GaugeVec
-	channelConfigLength *prometheus.GaugeVec
+	configWorkers        *prometheus.GaugeVec
+	channelLength        *prometheus.GaugeVec
+	channelConfigLength  *prometheus.GaugeVec
+	tlsCertificateExpiry *prometheus.GaugeVec
 
 	// Histograms
 	histRequestsDuration         *prometheus.HistogramVec
@@ -41,6 +68,20 @@ type appMetrics struct {
 	histTLSHandshakeDuration     *prometheus.HistogramVec
 	histWroteRequestBodyDuration *prometheus.HistogramVec
 	histResponseDuration         *prometheus.HistogramVec
+	histTLSHandshakeByType       *prometheus.HistogramVec
+	histProxyConnectDuration     *prometheus.HistogramVec
+	histConnectionIdleDuration   *prometheus.HistogramVec
+	histRequestsByNameDuration   *prometheus.HistogramVec
+	histRequestsByTargetDuration *prometheus.HistogramVec
+	histReplayScheduleLag        *prometheus.HistogramVec
+	histAuthTokenFetchDuration   *prometheus.HistogramVec
+	histRedirectHopDuration      *prometheus.HistogramVec
+	histDecompressDuration       *prometheus.HistogramVec
+	histUploadThroughput         *prometheus.HistogramVec
+	histRequestsBodySize         *prometheus.HistogramVec
+	histTimeToLastByte           *prometheus.HistogramVec
+	histDownloadThroughput       *prometheus.HistogramVec
+	histSSEInterEventLatency     *prometheus.HistogramVec
 
 	// Summaries
 	summaryRequestsDuration         *prometheus.SummaryVec
@@ -50,6 +91,20 @@ type appMetrics struct {
 	summaryTLSHandshakeDuration     *prometheus.SummaryVec
 	summaryWroteRequestBodyDuration *prometheus.SummaryVec
 	summaryResponseDuration         *prometheus.SummaryVec
+	summaryTLSHandshakeByType       *prometheus.SummaryVec
+	summaryProxyConnectDuration     *prometheus.SummaryVec
+	summaryConnectionIdleDuration   *prometheus.SummaryVec
+	summaryRequestsByNameDuration   *prometheus.SummaryVec
+	summaryRequestsByTargetDuration *prometheus.SummaryVec
+	summaryReplayScheduleLag        *prometheus.SummaryVec
+	summaryAuthTokenFetchDuration   *prometheus.SummaryVec
+	summaryRedirectHopDuration      *prometheus.SummaryVec
+	summaryDecompressDuration       *prometheus.SummaryVec
+	summaryUploadThroughput         *prometheus.SummaryVec
+	summaryRequestsBodySize         *prometheus.SummaryVec
+	summaryTimeToLastByte           *prometheus.SummaryVec
+	summaryDownloadThroughput       *prometheus.SummaryVec
+	summarySSEInterEventLatency     *prometheus.SummaryVec
 }
 
 func initMetrics(config appConfig, labelNames, labelValues []string) appMetrics {
@@ -79,11 +134,111 @@ func initMetrics(config appConfig, labelNames, labelValues []string) appMetrics
 			Namespace: "minigun",
 			Subsystem: "requests",
 			Name:      "bytes_sum",
-			Help:      "The total number of bytes received from remote endpoint",
+			Help:      "The total number of request body bytes sent to remote endpoint, compressed with -send-compress",
 		},
 		am.labelNames,
 	)
 
+	am.requestsSendRawBytesSum = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "raw_bytes_sum",
+			Help:      "The total number of request body bytes before compression",
+		},
+		am.labelNames,
+	)
+
+	am.requestsByAddress = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "by_address_total",
+			Help:      "The total number of requests sent per remote endpoint address",
+		},
+		append(am.labelNames, "address"),
+	)
+
+	am.requestsBySourceIP = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "by_source_ip_total",
+			Help:      "The total number of requests sent per local source IP",
+		},
+		append(am.labelNames, "source_ip"),
+	)
+
+	am.errorsBySourceIP = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "errors_by_source_ip_total",
+			Help:      "The total number of errors when sending requests per local source IP",
+		},
+		append(am.labelNames, "source_ip"),
+	)
+
+	am.requestsByName = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "by_name_total",
+			Help:      "The total number of requests sent per request from the request list",
+		},
+		append(am.labelNames, "request"),
+	)
+
+	am.errorsByName = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "errors_by_name_total",
+			Help:      "The total number of errors when sending requests per request from the request list",
+		},
+		append(am.labelNames, "request"),
+	)
+
+	am.requestsByTarget = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "by_target_total",
+			Help:      "The total number of requests sent per target from -targets",
+		},
+		append(am.labelNames, "target"),
+	)
+
+	am.errorsByTarget = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "errors_by_target_total",
+			Help:      "The total number of errors when sending requests per target from -targets",
+		},
+		append(am.labelNames, "target"),
+	)
+
+	am.sessionExtractedVariables = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "session",
+			Name:      "extracted_variables_total",
+			Help:      "The total number of variables extracted from responses per variable",
+		},
+		append(am.labelNames, "variable"),
+	)
+
+	am.sessionUnresolvedVariables = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "session",
+			Name:      "unresolved_variables_total",
+			Help:      "The total number of variable references which could not be resolved because the variable was not extracted yet",
+		},
+		append(am.labelNames, "variable"),
+	)
+
 	am.requestsSendSuccess = promauto.With(registry).NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "minigun",
@@ -218,6 +373,350 @@ func initMetrics(config appConfig, labelNames, labelValues []string) appMetrics
 		am.labelNames,
 	)
 
+	// Connection reuse metrics
+	am.connectionsGot = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "httptrace",
+			Name:      "got_connections_total",
+			Help:      "The total number of connections obtained for requests, by whether the connection was reused",
+		},
+		append(am.labelNames, "reused"),
+	)
+
+	am.connectionsRecycled = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "runtime",
+			Name:      "recycled_connections_total",
+			Help:      "The total number of connections closed by minigun after max requests or max age is reached",
+		},
+		append(am.labelNames, "reason"),
+	)
+
+	am.histConnectionIdleDuration = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "httptrace",
+			Name:      "hist_connection_idle_duration_seconds",
+			Help:      "Histogram distribution of durations reused connections were idle for, in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summaryConnectionIdleDuration = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "httptrace",
+			Name:       "connection_idle_duration_seconds",
+			Help:       "Summary distribution of durations reused connections were idle for, in seconds",
+			Objectives: summaryObjectives,
+		},
+		am.labelNames,
+	)
+
+	// Per request metrics for request lists
+	am.histRequestsByNameDuration = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "hist_by_name_duration_seconds",
+			Help:      "Histogram distribution of full request durations per request from the request list, in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		append(am.labelNames, "request"),
+	)
+
+	am.summaryRequestsByNameDuration = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "requests",
+			Name:       "by_name_duration_seconds",
+			Help:       "Summary distribution of full request durations per request from the request list, in seconds",
+			Objectives: summaryObjectives,
+		},
+		append(am.labelNames, "request"),
+	)
+
+	// Per target metrics for multiple targets
+	am.histRequestsByTargetDuration = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "hist_by_target_duration_seconds",
+			Help:      "Histogram distribution of full request durations per target from -targets, in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		append(am.labelNames, "target"),
+	)
+
+	am.summaryRequestsByTargetDuration = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "requests",
+			Name:       "by_target_duration_seconds",
+			Help:       "Summary distribution of full request durations per target from -targets, in seconds",
+			Objectives: summaryObjectives,
+		},
+		append(am.labelNames, "target"),
+	)
+
+	// Replay metrics
+	am.histReplayScheduleLag = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "replay",
+			Name:      "hist_schedule_lag_seconds",
+			Help:      "Histogram distribution of delays between scheduled and actual replay times of recorded requests, in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summaryReplayScheduleLag = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "replay",
+			Name:       "schedule_lag_seconds",
+			Help:       "Summary distribution of delays between scheduled and actual replay times of recorded requests, in seconds",
+			Objectives: summaryObjectives,
+		},
+		am.labelNames,
+	)
+
+	// Auth metrics, token requests are not benchmarked requests
+	am.authTokenFetches = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "auth",
+			Name:      "token_fetches_total",
+			Help:      "The total number of auth token requests or JWT signings",
+		},
+		am.labelNames,
+	)
+
+	am.authTokenFetchErrors = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "auth",
+			Name:      "token_fetch_errors_total",
+			Help:      "The total number of failed auth token requests",
+		},
+		am.labelNames,
+	)
+
+	am.histAuthTokenFetchDuration = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "auth",
+			Name:      "hist_token_fetch_duration_seconds",
+			Help:      "Histogram distribution of auth token request durations, in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summaryAuthTokenFetchDuration = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "auth",
+			Name:       "token_fetch_duration_seconds",
+			Help:       "Summary distribution of auth token request durations, in seconds",
+			Objectives: summaryObjectives,
+		},
+		am.labelNames,
+	)
+
+	// Streamed upload metrics
+	am.histUploadThroughput = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "hist_upload_throughput_bytes_per_second",
+			Help:      "Histogram distribution of streamed request body upload throughput per request, in bytes per second",
+			Buckets:   throughputBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summaryUploadThroughput = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "requests",
+			Name:       "upload_throughput_bytes_per_second",
+			Help:       "Summary distribution of streamed request body upload throughput per request, in bytes per second",
+			Objectives: summaryObjectives,
+		},
+		am.labelNames,
+	)
+
+	// Request body size metrics
+	am.histRequestsBodySize = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "hist_body_size_bytes",
+			Help:      "Histogram distribution of request body size per request, in bytes before compression",
+			Buckets:   bodySizeBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summaryRequestsBodySize = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "requests",
+			Name:       "body_size_bytes",
+			Help:       "Summary distribution of request body size per request, in bytes before compression",
+			Objectives: summaryObjectives,
+		},
+		am.labelNames,
+	)
+
+	// Streamed response metrics
+	am.histTimeToLastByte = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "response",
+			Name:      "hist_time_to_last_byte_seconds",
+			Help:      "Histogram distribution of the time since the request start and until the last byte of streamed response is read, in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summaryTimeToLastByte = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "response",
+			Name:       "time_to_last_byte_seconds",
+			Help:       "Summary distribution of the time since the request start and until the last byte of streamed response is read, in seconds",
+			Objectives: summaryObjectives,
+		},
+		am.labelNames,
+	)
+
+	am.histDownloadThroughput = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "response",
+			Name:      "hist_download_throughput_bytes_per_second",
+			Help:      "Histogram distribution of streamed response body download throughput per response, in bytes per second",
+			Buckets:   throughputBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summaryDownloadThroughput = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "response",
+			Name:       "download_throughput_bytes_per_second",
+			Help:       "Summary distribution of streamed response body download throughput per response, in bytes per second",
+			Objectives: summaryObjectives,
+		},
+		am.labelNames,
+	)
+
+	am.sseEvents = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "sse",
+			Name:      "events_total",
+			Help:      "The total number of received server-sent events",
+		},
+		am.labelNames,
+	)
+
+	am.histSSEInterEventLatency = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "sse",
+			Name:      "hist_inter_event_latency_seconds",
+			Help:      "Histogram distribution of the time between consecutive server-sent events of the same response, in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summarySSEInterEventLatency = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "sse",
+			Name:       "inter_event_latency_seconds",
+			Help:       "Summary distribution of the time between consecutive server-sent events of the same response, in seconds",
+			Objectives: summaryObjectives,
+		},
+		am.labelNames,
+	)
+
+	// Redirect metrics
+	am.redirects = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "redirects",
+			Name:      "total",
+			Help:      "The total number of followed redirects per redirect status",
+		},
+		append(am.labelNames, "status"),
+	)
+
+	am.requestsByFirstStatus = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "requests",
+			Name:      "by_first_status_total",
+			Help:      "The total number of responses per status of the first response, before any redirects",
+		},
+		append(am.labelNames, "status"),
+	)
+
+	am.histRedirectHopDuration = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "redirects",
+			Name:      "hist_hop_duration_seconds",
+			Help:      "Histogram distribution of every hop durations of redirected requests, in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summaryRedirectHopDuration = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "redirects",
+			Name:       "hop_duration_seconds",
+			Help:       "Summary distribution of every hop durations of redirected requests, in seconds",
+			Objectives: summaryObjectives,
+		},
+		am.labelNames,
+	)
+
+	// Proxy CONNECT metrics
+	am.histProxyConnectDuration = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "httptrace",
+			Name:      "hist_proxy_connect_duration_seconds",
+			Help:      "Histogram distribution of proxy CONNECT durations, in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summaryProxyConnectDuration = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "httptrace",
+			Name:       "proxy_connect_duration_seconds",
+			Help:       "Summary distribution of proxy CONNECT durations, in seconds",
+			Objectives: summaryObjectives,
+		},
+		am.labelNames,
+	)
+
 	// Request body sent metrics
 	am.histWroteRequestBodyDuration = promauto.With(registry).NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -257,7 +756,49 @@ func initMetrics(config appConfig, labelNames, labelValues []string) appMetrics
 			Namespace: "minigun",
 			Subsystem: "response",
 			Name:      "bytes_sum",
-			Help:      "The sum of response bytes",
+			Help:      "The sum of response bytes, as received on the wire",
+		},
+		am.labelNames,
+	)
+
+	am.responseDecodedBytesSum = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "response",
+			Name:      "decoded_bytes_sum",
+			Help:      "The sum of response bytes after decompression",
+		},
+		am.labelNames,
+	)
+
+	am.responsesByEncoding = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "response",
+			Name:      "by_encoding_total",
+			Help:      "The total number of responses per content encoding",
+		},
+		append(am.labelNames, "encoding"),
+	)
+
+	am.histDecompressDuration = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "response",
+			Name:      "hist_decompress_duration_seconds",
+			Help:      "Histogram distribution of response decompression durations, in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		am.labelNames,
+	)
+
+	am.summaryDecompressDuration = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "response",
+			Name:       "decompress_duration_seconds",
+			Help:       "Summary distribution of response decompression durations, in seconds",
+			Objectives: summaryObjectives,
 		},
 		am.labelNames,
 	)
@@ -284,6 +825,60 @@ func initMetrics(config appConfig, labelNames, labelValues []string) appMetrics
 		append(am.labelNames, "status"),
 	)
 
+	// TLS metrics
+	am.tlsConnections = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "tls",
+			Name:      "connections_total",
+			Help:      "The total number of TLS connections by negotiated TLS version, cipher suite, ALPN protocol and handshake type",
+		},
+		append(am.labelNames, "tls_version", "tls_cipher", "tls_alpn", "tls_handshake"),
+	)
+
+	am.histTLSHandshakeByType = promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "minigun",
+			Subsystem: "tls",
+			Name:      "hist_handshake_duration_seconds",
+			Help:      "Histogram distribution of TLS Handshake durations by handshake type (full or resumed), in seconds",
+			Buckets:   secondsDurationBuckets,
+		},
+		append(am.labelNames, "tls_handshake"),
+	)
+
+	am.summaryTLSHandshakeByType = promauto.With(registry).NewSummaryVec(
+		prometheus.SummaryOpts{
+			Namespace:  "minigun",
+			Subsystem:  "tls",
+			Name:       "handshake_duration_seconds",
+			Help:       "Summary distribution of TLS Handshake durations by handshake type (full or resumed), in seconds",
+			Objectives: summaryObjectives,
+		},
+		append(am.labelNames, "tls_handshake"),
+	)
+
+	am.tlsCertificateExpiry = promauto.With(registry).NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "minigun",
+			Subsystem: "tls",
+			Name:      "certificate_expiry_timestamp_seconds",
+			Help:      "Expiry time of the remote endpoint certificate, in seconds since epoch",
+		},
+		append(am.labelNames, "tls_subject"),
+	)
+
+	// QUIC metrics
+	am.quicZeroRTTConnections = promauto.With(registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "minigun",
+			Subsystem: "quic",
+			Name:      "zero_rtt_connections_total",
+			Help:      "The total number of QUIC connections established with 0-RTT",
+		},
+		am.labelNames,
+	)
+
 	// App health metrics
 	am.configWorkers = promauto.With(registry).NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -398,6 +993,31 @@ func getSummaryLabelValues(reg *prometheus.Registry, name string, label string)
 	return result, nil
 }
 
+// Get counter or gauge values by metric name, summed up per value of the label
+func getMetricValuesByLabel(reg *prometheus.Registry, name string, label string) (map[string]float64, error) {
+	result := make(map[string]float64)
+
+	metrics, err := prometheus.Gatherer(reg).Gather()
+	if err != nil {
+		return result, err
+	}
+
+	for _, mF := range metrics {
+		switch *mF.Name {
+		case name:
+			for _, m := range mF.Metric {
+				for _, lp := range m.GetLabel() {
+					if lp.GetName() == label {
+						result[lp.GetValue()] += m.GetCounter().GetValue() + m.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
 // Get counter value by metric name
 func getCountSumFromSummary(reg *prometheus.Registry, name string, labels map[string]string) (uint64, float64, error) {
 
@@ -439,8 +1059,19 @@ func getCountSumFromSummary(reg *prometheus.Registry, name string, labels map[st
 	return count, sum, fmt.Errorf("Metric %s not found", name)
 }
 
+// Get the first summary metric from the family which matches labels
+func getMatchedSummary(mF *pcm.MetricFamily, labels map[string]string) *pcm.Summary {
+	for _, m := range mF.Metric {
+		if m.Summary != nil && labelMatched(labels, m.GetLabel()) {
+			return m.Summary
+		}
+	}
+
+	return nil
+}
+
 // Get summary quantile values by metric name, returns a list of values
-func getQuantileValuesByName(reg *prometheus.Registry, name string) ([]float64, error) {
+func getQuantileValuesByName(reg *prometheus.Registry, name string, labels map[string]string) ([]float64, error) {
 	result := make([]float64, 0)
 
 	metrics, err := prometheus.Gatherer(reg).Gather()
@@ -451,9 +1082,8 @@ func getQuantileValuesByName(reg *prometheus.Registry, name string) ([]float64,
 	for _, mF := range metrics {
 		switch *mF.Name {
 		case name:
-			m := *mF.Metric[0]
-			if m.Summary != nil {
-				quantiles := m.Summary.GetQuantile()
+			if summary := getMatchedSummary(mF, labels); summary != nil {
+				quantiles := summary.GetQuantile()
 				for _, q := range quantiles {
 					result = append(result, q.GetValue())
 				}
@@ -465,7 +1095,7 @@ func getQuantileValuesByName(reg *prometheus.Registry, name string) ([]float64,
 }
 
 // Get summary quantiles by metric name, returns a map of quantiles
-func getQuantilesByName(reg *prometheus.Registry, name string) (map[float64]float64, error) {
+func getQuantilesByName(reg *prometheus.Registry, name string, labels map[string]string) (map[float64]float64, error) {
 	result := make(map[float64]float64)
 
 	metrics, err := prometheus.Gatherer(reg).Gather()
@@ -476,9 +1106,8 @@ func getQuantilesByName(reg *prometheus.Registry, name string) (map[float64]floa
 	for _, mF := range metrics {
 		switch *mF.Name {
 		case name:
-			m := *mF.Metric[0]
-			if m.Summary != nil {
-				quantiles := m.Summary.GetQuantile()
+			if summary := getMatchedSummary(mF, labels); summary != nil {
+				quantiles := summary.GetQuantile()
 				for _, q := range quantiles {
 					result[q.GetQuantile()] = q.GetValue()
 				}
@@ -504,7 +1133,7 @@ func getSummaryValues(reg *prometheus.Registry, name string, labels map[string]s
 
 	if requests, seconds, err = getCountSumFromSummary(reg, name, labels); err == nil && requests > 0 {
 		mean = seconds / float64(requests)
-		quantiles, err = getQuantilesByName(reg, name)
+		quantiles, err = getQuantilesByName(reg, name, labels)
 		if err != nil {
 			applog.Errorf("Got error from getQuantilesByName: %s", err.Error())
 			return requests, seconds, mean, quantiles, err
@@ -542,7 +1171,7 @@ func addSummaryToReport(outMatrix printMatrix, header string, reg *prometheus.Re
 
 	if requests, seconds, err := getCountSumFromSummary(reg, name, labels); err == nil && requests > 0 {
 		requestTime := humanizeDurationSeconds(seconds / float64(requests))
-		quantiles, err := getQuantileValuesByName(reg, name)
+		quantiles, err := getQuantileValuesByName(reg, name, labels)
 		if err != nil {
 			applog.Errorf("Got error from getQuantilesByName: %s", err.Error())
 		}
@@ -553,3 +1182,30 @@ func addSummaryToReport(outMatrix printMatrix, header string, reg *prometheus.Re
 
 	return outMatrix
 }
+
+// Add prometheus counter values per label value to report matrix, in "[value:count]" form
+func addCounterValuesToReport(outMatrix printMatrix, name string, reg *prometheus.Registry, metric string, label string) printMatrix {
+
+	values, err := getMetricValuesByLabel(reg, metric, label)
+	if err != nil {
+		applog.Errorf("Error getting Prometheus counter values: %v", err.Error())
+		return outMatrix
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	valuesReport := make([]string, 0, len(keys))
+	for _, k := range keys {
+		valuesReport = append(valuesReport, fmt.Sprintf("[%v:%v]", k, values[k]))
+	}
+
+	if len(valuesReport) > 0 {
+		outMatrix = append(outMatrix, printRow{name, strings.Join(valuesReport, " ")})
+	}
+
+	return outMatrix
+}
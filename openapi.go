@@ -0,0 +1,634 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Max depth of generated values, specs could have recursive schemas
+const openAPIMaxDepth = 8
+
+// HTTP methods in the order they are listed in OpenAPI path items
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Parsed OpenAPI document, we work with generic maps to support both YAML and JSON specs
+type openAPISpec struct {
+	doc map[string]any
+}
+
+// Custom type to parse "name[:weight]" comma separated lists of operations and tags
+type openAPIWeights map[string]int
+
+func parseOpenAPIWeights(value string) (openAPIWeights, error) {
+	weights := make(openAPIWeights)
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, weight, hasWeight := strings.Cut(item, ":")
+		weights[name] = 1
+		if hasWeight {
+			w, err := strconv.Atoi(weight)
+			if err != nil || w < 1 {
+				return nil, fmt.Errorf("wrong weight in %q, expected positive integer", item)
+			}
+			weights[name] = w
+		}
+	}
+
+	return weights, nil
+}
+
+// Load OpenAPI 3 spec and generate a request per selected operation, repeated according to weights
+func loadOpenAPI(fileName string, baseURL string, operations string, tags string) ([]requestSpec, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := openAPISpec{}
+	if err := yaml.Unmarshal(data, &spec.doc); err != nil {
+		return nil, fmt.Errorf("error parsing OpenAPI spec: %s", err.Error())
+	}
+
+	if version, _ := spec.doc["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("only OpenAPI 3 specs are supported")
+	}
+
+	operationWeights, err := parseOpenAPIWeights(operations)
+	if err != nil {
+		return nil, fmt.Errorf("wrong operations list: %s", err.Error())
+	}
+
+	tagWeights, err := parseOpenAPIWeights(tags)
+	if err != nil {
+		return nil, fmt.Errorf("wrong tags list: %s", err.Error())
+	}
+
+	// Default to the first server URL
+	if baseURL == "" {
+		if servers, _ := spec.doc["servers"].([]any); len(servers) > 0 {
+			server, _ := servers[0].(map[string]any)
+			baseURL, _ = server["url"].(string)
+		}
+		if err := validateUrl(baseURL); err != nil {
+			return nil, fmt.Errorf("no absolute server URL in OpenAPI spec, specify -fire-target: %s", err.Error())
+		}
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	paths, _ := spec.doc["paths"].(map[string]any)
+	pathNames := make([]string, 0, len(paths))
+	for path := range paths {
+		pathNames = append(pathNames, path)
+	}
+	sort.Strings(pathNames)
+
+	requests := make([]requestSpec, 0)
+	weights := make([]int, 0)
+
+	for _, path := range pathNames {
+		pathItem := spec.resolve(paths[path])
+
+		for _, method := range openAPIMethods {
+			operation, ok := pathItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			operationID, _ := operation["operationId"].(string)
+			if operationID == "" {
+				operationID = strings.ToUpper(method) + " " + path
+			}
+
+			weight := openAPIOperationWeight(operation, operationID, operationWeights, tagWeights)
+			if weight == 0 {
+				continue
+			}
+
+			request, err := spec.generateRequest(baseURL, path, method, pathItem, operation)
+			if err != nil {
+				return nil, fmt.Errorf("error generating request for operation %q: %s", operationID, err.Error())
+			}
+			request.name = operationID
+
+			requests = append(requests, request)
+			weights = append(weights, weight)
+		}
+	}
+
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("no OpenAPI operations selected")
+	}
+
+	return weightedRequests(requests, weights), nil
+}
+
+// Operation weight, 0 means operation is not selected. Every operation is selected if there are no filters
+func openAPIOperationWeight(operation map[string]any, operationID string, operationWeights, tagWeights openAPIWeights) int {
+	if len(operationWeights) == 0 && len(tagWeights) == 0 {
+		return 1
+	}
+
+	if weight, ok := operationWeights[operationID]; ok {
+		return weight
+	}
+
+	operationTags, _ := operation["tags"].([]any)
+	for _, tag := range operationTags {
+		if name, ok := tag.(string); ok && tagWeights[name] > 0 {
+			return tagWeights[name]
+		}
+	}
+
+	return 0
+}
+
+// Generate request with parameters and body for the operation
+func (spec openAPISpec) generateRequest(baseURL, path, method string, pathItem, operation map[string]any) (requestSpec, error) {
+	request := requestSpec{method: strings.ToUpper(method), headers: make(httpHeaders)}
+	query := url.Values{}
+	cookies := make([]string, 0)
+
+	// Path level parameters first, operation parameters override them
+	parameters := make(map[string]map[string]any)
+	for _, source := range []map[string]any{pathItem, operation} {
+		list, _ := source["parameters"].([]any)
+		for _, item := range list {
+			parameter := spec.resolve(item)
+			name, _ := parameter["name"].(string)
+			in, _ := parameter["in"].(string)
+			parameters[in+":"+name] = parameter
+		}
+	}
+
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		parameter := parameters[key]
+		name, _ := parameter["name"].(string)
+		in, _ := parameter["in"].(string)
+		required, _ := parameter["required"].(bool)
+
+		value, hasExample := openAPIExample(parameter)
+		if !hasExample {
+			// Optional parameters are sent only when spec has an example for them
+			if !required && in != "path" {
+				continue
+			}
+			value = spec.generateValue(parameter["schema"], 0)
+		}
+
+		switch in {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(formatOpenAPIParameter(value)))
+		case "query":
+			query.Add(name, formatOpenAPIParameter(value))
+		case "header":
+			request.headers[name] = formatOpenAPIParameter(value)
+		case "cookie":
+			cookies = append(cookies, name+"="+formatOpenAPIParameter(value))
+		}
+	}
+
+	request.url = baseURL + path
+	if len(query) > 0 {
+		request.url += "?" + query.Encode()
+	}
+
+	if len(cookies) > 0 {
+		request.headers["Cookie"] = strings.Join(cookies, "; ")
+	}
+
+	if body := spec.resolve(operation["requestBody"]); body != nil {
+		mediaType, media := spec.pickMediaType(body)
+		if mediaType != "" {
+			payload, err := spec.generateBody(mediaType, media)
+			if err != nil {
+				return request, err
+			}
+			request.payload = payload
+			request.headers["Content-Type"] = mediaType
+		}
+	}
+
+	return request, nil
+}
+
+// Pick the request body media type we can generate, JSON is preferred
+func (spec openAPISpec) pickMediaType(body map[string]any) (string, map[string]any) {
+	content, _ := body["content"].(map[string]any)
+
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+
+	for _, preferred := range []string{"application/json", "application/x-www-form-urlencoded"} {
+		for _, mediaType := range mediaTypes {
+			if strings.HasPrefix(mediaType, preferred) {
+				media, _ := content[mediaType].(map[string]any)
+				return mediaType, media
+			}
+		}
+	}
+
+	// Structured syntax suffix, like application/merge-patch+json
+	for _, mediaType := range mediaTypes {
+		if strings.HasSuffix(mediaType, "+json") {
+			media, _ := content[mediaType].(map[string]any)
+			return mediaType, media
+		}
+	}
+
+	if len(mediaTypes) > 0 {
+		media, _ := content[mediaTypes[0]].(map[string]any)
+		return mediaTypes[0], media
+	}
+
+	return "", nil
+}
+
+// Generate request body for the media type, example values are preferred
+func (spec openAPISpec) generateBody(mediaType string, media map[string]any) ([]byte, error) {
+	value, ok := openAPIExample(media)
+	if !ok {
+		value = spec.generateValue(media["schema"], 0)
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "application/x-www-form-urlencoded"):
+		form := url.Values{}
+		if fields, ok := value.(map[string]any); ok {
+			for name, field := range fields {
+				form.Add(name, formatOpenAPIParameter(field))
+			}
+		}
+		return []byte(form.Encode()), nil
+
+	case strings.HasPrefix(mediaType, "application/json") || strings.HasSuffix(mediaType, "+json"):
+		return json.Marshal(value)
+
+	default:
+		return []byte(formatOpenAPIParameter(value)), nil
+	}
+}
+
+// Resolve local "$ref" references, like "#/components/schemas/Pet"
+func (spec openAPISpec) resolve(value any) map[string]any {
+	for depth := 0; depth < openAPIMaxDepth; depth++ {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object
+		}
+
+		value = spec.lookup(ref)
+	}
+
+	return nil
+}
+
+// Get document node by JSON pointer
+func (spec openAPISpec) lookup(ref string) any {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+
+	var node any = spec.doc
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		node = object[part]
+	}
+
+	return node
+}
+
+// Generate a valid value for the schema
+func (spec openAPISpec) generateValue(schemaRef any, depth int) any {
+	schema := spec.resolve(schemaRef)
+	if schema == nil || depth > openAPIMaxDepth {
+		return nil
+	}
+
+	if value, ok := openAPIExample(schema); ok {
+		return value
+	}
+
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if allOf, ok := schema["allOf"].([]any); ok {
+		result := make(map[string]any)
+		for _, item := range allOf {
+			if object, ok := spec.generateValue(item, depth+1).(map[string]any); ok {
+				for k, v := range object {
+					result[k] = v
+				}
+			}
+		}
+		return result
+	}
+
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if items, ok := schema[key].([]any); ok && len(items) > 0 {
+			return spec.generateValue(items[0], depth+1)
+		}
+	}
+
+	schemaType, _ := schema["type"].(string)
+	// OpenAPI 3.1 allows a list of types
+	if types, ok := schema["type"].([]any); ok {
+		for _, t := range types {
+			if schemaType, _ = t.(string); schemaType != "null" {
+				break
+			}
+		}
+	}
+
+	switch schemaType {
+	case "string":
+		return openAPIString(schema)
+	case "integer":
+		return openAPINumber(schema, true)
+	case "number":
+		return openAPINumber(schema, false)
+	case "boolean":
+		return true
+	case "array":
+		return []any{spec.generateValue(schema["items"], depth+1)}
+	case "object", "":
+		if _, ok := schema["properties"]; !ok && schemaType == "" {
+			return nil
+		}
+		result := make(map[string]any)
+		properties, _ := schema["properties"].(map[string]any)
+		for name, property := range properties {
+			if propertySchema := spec.resolve(property); propertySchema != nil && propertySchema["readOnly"] == true {
+				continue
+			}
+			result[name] = spec.generateValue(property, depth+1)
+		}
+		return result
+	}
+
+	return nil
+}
+
+// Get example value from a schema, parameter or media type object
+func openAPIExample(object map[string]any) (any, bool) {
+	if object == nil {
+		return nil, false
+	}
+
+	if value, ok := object["example"]; ok {
+		return value, true
+	}
+
+	// Named examples for parameters and media types, OpenAPI 3.1 list of examples for schemas
+	switch examples := object["examples"].(type) {
+	case map[string]any:
+		names := make([]string, 0, len(examples))
+		for name := range examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if example, ok := examples[name].(map[string]any); ok {
+				if value, ok := example["value"]; ok {
+					return value, true
+				}
+			}
+		}
+	case []any:
+		if len(examples) > 0 {
+			return examples[0], true
+		}
+	}
+
+	return nil, false
+}
+
+// Generate string value by format, respecting length limits
+func openAPIString(schema map[string]any) string {
+	value := "string"
+
+	switch schema["format"] {
+	case "date-time":
+		value = "2024-01-01T00:00:00Z"
+	case "date":
+		value = "2024-01-01"
+	case "time":
+		value = "00:00:00"
+	case "uuid":
+		value = "00000000-0000-4000-8000-000000000000"
+	case "email":
+		value = "user@example.com"
+	case "uri", "url":
+		value = "https://example.com/"
+	case "hostname":
+		value = "example.com"
+	case "ipv4":
+		value = "192.0.2.1"
+	case "ipv6":
+		value = "2001:db8::1"
+	case "byte":
+		value = "c3RyaW5n"
+	}
+
+	if minLength, ok := openAPIInt(schema["minLength"]); ok && len(value) < minLength {
+		value += strings.Repeat("x", minLength-len(value))
+	}
+	if maxLength, ok := openAPIInt(schema["maxLength"]); ok && len(value) > maxLength {
+		value = value[:maxLength]
+	}
+
+	return value
+}
+
+// Generate number within limits, the minimum if there is one
+func openAPINumber(schema map[string]any, integer bool) any {
+	low, lowExclusive := openAPILimit(schema, "minimum", "exclusiveMinimum", math.Inf(-1))
+	high, highExclusive := openAPILimit(schema, "maximum", "exclusiveMaximum", math.Inf(1))
+
+	// Integers within the limits, so minimum 1.5 is 2 and exclusive minimum 2 is 3
+	if integer {
+		if lowExclusive {
+			low = math.Floor(low) + 1
+		} else {
+			low = math.Ceil(low)
+		}
+		if highExclusive {
+			high = math.Ceil(high) - 1
+		} else {
+			high = math.Floor(high)
+		}
+		lowExclusive, highExclusive = false, false
+	}
+
+	value := float64(1)
+	if !math.IsInf(low, -1) {
+		value = low
+		if lowExclusive {
+			value = low + 1
+		}
+	}
+
+	if value > high || highExclusive && value >= high {
+		value = high
+		if highExclusive {
+			value = high - 1
+			if value < low || lowExclusive && value <= low {
+				value = (low + high) / 2
+			}
+		}
+	}
+
+	if integer {
+		return int64(value)
+	}
+
+	return value
+}
+
+// Limit and whether it's exclusive. OpenAPI 3.0 has boolean exclusiveMinimum and exclusiveMaximum, 3.1 has them
+// as numbers, the tighter limit applies if both are set
+func openAPILimit(schema map[string]any, name string, exclusiveName string, unbounded float64) (float64, bool) {
+	limit, bounded := openAPIFloat(schema[name])
+	if !bounded {
+		limit = unbounded
+	}
+
+	switch v := schema[exclusiveName].(type) {
+	case bool:
+		return limit, v && bounded
+	default:
+		exclusive, ok := openAPIFloat(v)
+		if ok && (!bounded || unbounded < 0 && exclusive >= limit || unbounded > 0 && exclusive <= limit) {
+			return exclusive, true
+		}
+	}
+
+	return limit, false
+}
+
+// YAML decoder returns int for integer values, JSON style float64 for the rest
+func openAPIInt(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+
+	return 0, false
+}
+
+// Numbers of YAML and JSON documents as float64
+func openAPIFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+// Format parameter value for URL, headers and form fields
+func formatOpenAPIParameter(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, formatOpenAPIParameter(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]any:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// Interleave requests according to their weights, so round-robin over the result sends them
+// in weighted proportions without bursts. It's smooth weighted round-robin, like in NGINX
+func weightedRequests(requests []requestSpec, weights []int) []requestSpec {
+	total := 0
+	divisor := 0
+	for _, weight := range weights {
+		total += weight
+		divisor = gcd(divisor, weight)
+	}
+
+	if total == len(requests) {
+		return requests
+	}
+
+	total /= divisor
+	current := make([]int, len(requests))
+	result := make([]requestSpec, 0, total)
+
+	for len(result) < total {
+		best := 0
+		for i, weight := range weights {
+			current[i] += weight / divisor
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= total
+		result = append(result, requests[best])
+	}
+
+	return result
+}
+
+// Greatest common divisor
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
@@ -0,0 +1,383 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Payload types with their default Content-Type
+var payloadContentTypes = map[string]string{
+	"json":     "application/json",
+	"text":     "text/plain",
+	"protobuf": "application/x-protobuf",
+	"msgpack":  "application/msgpack",
+	"cbor":     "application/cbor",
+	"form":     "application/x-www-form-urlencoded",
+	"raw":      "application/octet-stream",
+}
+
+// Payload types which are authored as JSON and encoded before sending
+var encodedPayloadTypes = map[string]bool{"protobuf": true, "msgpack": true, "cbor": true, "form": true}
+
+// Sorted list of payload types for messages
+func payloadTypes() []string {
+	types := make([]string, 0, len(payloadContentTypes))
+	for t := range payloadContentTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// Encode payload of the -send-payload-type, json, text and raw payloads are sent as is
+func encodePayload(config appConfig, data []byte) ([]byte, error) {
+	if config.sendPayloadType == "protobuf" {
+		return encodeProtobuf(data, config.protoDescriptor, config.protoMessage)
+	}
+	if !encodedPayloadTypes[config.sendPayloadType] {
+		return data, nil
+	}
+
+	value, err := decodeJSONPayload(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	switch config.sendPayloadType {
+	case "msgpack":
+		err = encodeMsgpack(&buf, value)
+	case "cbor":
+		err = encodeCBOR(&buf, value)
+	case "form":
+		err = encodeFormPayload(&buf, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Encode -send-file payload of an encoded payload type once at startup. It's an error to have no payload,
+// so we don't send empty bodies with the encoded Content-Type
+func encodeStartupPayload(config appConfig) ([]byte, error) {
+	if config.sendStream || config.sendBodySize > 0 {
+		return nil, fmt.Errorf("%s payloads are encoded from -send-file, they can't be streamed or generated", config.sendPayloadType)
+	}
+	if len(config.sendPayload) == 0 {
+		return nil, fmt.Errorf("%s payloads are encoded from -send-file, which is not set or empty", config.sendPayloadType)
+	}
+	if sessionVariableRegexp.Match(config.sendPayload) {
+		return nil, fmt.Errorf("session variables can't be used in %s payloads, they're encoded once at startup", config.sendPayloadType)
+	}
+
+	encoded, err := encodePayload(config, config.sendPayload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding %s payload: %s", config.sendPayloadType, err.Error())
+	}
+
+	return encoded, nil
+}
+
+// Decode JSON payload keeping numbers as they are, so integers are not encoded as floats
+func decodeJSONPayload(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("payload is not valid JSON: %s", err.Error())
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("payload is not valid JSON: unexpected data after the top level value")
+	}
+
+	return value, nil
+}
+
+// Encode JSON payload as protobuf message from a descriptor set, made with protoc --include_imports --descriptor_set_out
+func encodeProtobuf(data []byte, descriptorFile string, messageName string) ([]byte, error) {
+	if descriptorFile == "" || messageName == "" {
+		return nil, fmt.Errorf("protobuf payload needs -proto-descriptor and -proto-message")
+	}
+
+	raw, err := os.ReadFile(descriptorFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("error parsing descriptor set %q: %s", descriptorFile, err.Error())
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("error loading descriptor set %q: %s", descriptorFile, err.Error())
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(messageName))
+	if err != nil {
+		return nil, fmt.Errorf("message %q not found in %q", messageName, descriptorFile)
+	}
+	messageDescriptor, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a message", messageName)
+	}
+
+	message := dynamicpb.NewMessage(messageDescriptor)
+	if err := protojson.Unmarshal(data, message); err != nil {
+		return nil, fmt.Errorf("error converting payload to %s: %s", messageName, err.Error())
+	}
+
+	// Deterministic marshaling keeps fields in field number order, dynamic messages don't otherwise
+	return proto.MarshalOptions{Deterministic: true}.Marshal(message)
+}
+
+// Sorted keys, so encoded maps are the same every time
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Encode decoded JSON value as MessagePack, with the smallest representation of every value
+func encodeMsgpack(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			encodeMsgpackInt(buf, i)
+		} else if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			buf.WriteByte(0xcf)
+			buf.Write(binary.BigEndian.AppendUint64(nil, u))
+		} else if f, err := v.Float64(); err == nil {
+			buf.WriteByte(0xcb)
+			buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+		} else {
+			return err
+		}
+	case string:
+		switch n := len(v); {
+		case n < 32:
+			buf.WriteByte(0xa0 | byte(n))
+		case n < 1<<8:
+			buf.Write([]byte{0xd9, byte(n)})
+		case n < 1<<16:
+			buf.WriteByte(0xda)
+			buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+		default:
+			buf.WriteByte(0xdb)
+			buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+		}
+		buf.WriteString(v)
+	case []any:
+		encodeMsgpackLength(buf, len(v), 0x90, 0xdc)
+		for _, item := range v {
+			if err := encodeMsgpack(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		encodeMsgpackLength(buf, len(v), 0x80, 0xde)
+		for _, key := range sortedKeys(v) {
+			if err := encodeMsgpack(buf, key); err != nil {
+				return err
+			}
+			if err := encodeMsgpack(buf, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value %v", v)
+	}
+
+	return nil
+}
+
+// Integers are fixints when they fit, otherwise the smallest sized int or uint type
+func encodeMsgpackInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i < 128:
+		buf.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		buf.WriteByte(byte(i))
+	case i >= 0 && i < 1<<8:
+		buf.Write([]byte{0xcc, byte(i)})
+	case i >= 0 && i < 1<<16:
+		buf.WriteByte(0xcd)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(i)))
+	case i >= 0 && i < 1<<32:
+		buf.WriteByte(0xce)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+	case i >= 0:
+		buf.WriteByte(0xcf)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+	case i >= math.MinInt8:
+		buf.Write([]byte{0xd0, byte(i)})
+	case i >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(i)))
+	case i >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+	default:
+		buf.WriteByte(0xd3)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+	}
+}
+
+// Array and map headers, fixed ones hold up to 15 items
+func encodeMsgpackLength(buf *bytes.Buffer, n int, fixed byte, sized byte) {
+	switch {
+	case n < 16:
+		buf.WriteByte(fixed | byte(n))
+	case n < 1<<16:
+		buf.WriteByte(sized)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(sized + 1)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+// Encode decoded JSON value as CBOR, integers and lengths use the shortest head
+func encodeCBOR(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(0xf6)
+	case bool:
+		if v {
+			buf.WriteByte(0xf5)
+		} else {
+			buf.WriteByte(0xf4)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			if i >= 0 {
+				encodeCBORHead(buf, 0, uint64(i))
+			} else {
+				encodeCBORHead(buf, 1, uint64(-(i + 1)))
+			}
+		} else if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			encodeCBORHead(buf, 0, u)
+		} else if f, err := v.Float64(); err == nil {
+			buf.WriteByte(0xfb)
+			buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+		} else {
+			return err
+		}
+	case string:
+		encodeCBORHead(buf, 3, uint64(len(v)))
+		buf.WriteString(v)
+	case []any:
+		encodeCBORHead(buf, 4, uint64(len(v)))
+		for _, item := range v {
+			if err := encodeCBOR(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		encodeCBORHead(buf, 5, uint64(len(v)))
+		for _, key := range sortedKeys(v) {
+			if err := encodeCBOR(buf, key); err != nil {
+				return err
+			}
+			if err := encodeCBOR(buf, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value %v", v)
+	}
+
+	return nil
+}
+
+// CBOR head is major type in 3 high bits, with the argument in the low bits or in the following bytes
+func encodeCBORHead(buf *bytes.Buffer, major byte, n uint64) {
+	major <<= 5
+
+	switch {
+	case n < 24:
+		buf.WriteByte(major | byte(n))
+	case n < 1<<8:
+		buf.Write([]byte{major | 24, byte(n)})
+	case n < 1<<16:
+		buf.WriteByte(major | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n < 1<<32:
+		buf.WriteByte(major | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		buf.WriteByte(major | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+// Encode JSON object as urlencoded form, arrays are repeated fields
+func encodeFormPayload(buf *bytes.Buffer, value any) error {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("form payload must be a JSON object")
+	}
+
+	values := make(url.Values)
+	for key, field := range object {
+		items, isArray := field.([]any)
+		if !isArray {
+			items = []any{field}
+		}
+
+		for _, item := range items {
+			switch v := item.(type) {
+			case string:
+				values.Add(key, v)
+			case json.Number, bool:
+				values.Add(key, fmt.Sprintf("%v", v))
+			case nil:
+				values.Add(key, "")
+			default:
+				return fmt.Errorf("form field %q must be a string, number, bool or an array of them", key)
+			}
+		}
+	}
+
+	// Encode sorts fields by name
+	buf.WriteString(values.Encode())
+
+	return nil
+}
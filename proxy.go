@@ -0,0 +1,81 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// httptrace has no hooks for proxy CONNECT, so we keep its start time in the request context
+type proxyConnectTraceKey struct{}
+
+type proxyConnectTrace struct {
+	start time.Time
+}
+
+// Check proxy URL
+func validateProxyUrl(inURL string) error {
+	if err := validateUrl(inURL); err != nil {
+		return err
+	}
+
+	u, _ := url.Parse(inURL)
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return nil
+	}
+
+	return fmt.Errorf("unsupported proxy scheme %q, supported schemes are http, https, socks5 and socks5h", u.Scheme)
+}
+
+// Init proxy func which respects no-proxy list
+func initProxyFunc(config appConfig) func(*http.Request) (*url.URL, error) {
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  config.proxy,
+		HTTPSProxy: config.proxy,
+		NoProxy:    config.noProxy,
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
+
+// Configure HTTP transport to use proxy and to measure proxy CONNECT durations
+func configureProxy(config appConfig, tr *http.Transport) {
+	if config.proxy == "" {
+		return
+	}
+
+	tr.Proxy = initProxyFunc(config)
+
+	tr.GetProxyConnectHeader = func(ctx context.Context, proxyURL *url.URL, target string) (http.Header, error) {
+		if trace, ok := ctx.Value(proxyConnectTraceKey{}).(*proxyConnectTrace); ok {
+			trace.start = time.Now()
+		}
+		return nil, nil
+	}
+
+	tr.OnProxyConnectResponse = func(ctx context.Context, proxyURL *url.URL, connectReq *http.Request, connectRes *http.Response) error {
+		if trace, ok := ctx.Value(proxyConnectTraceKey{}).(*proxyConnectTrace); ok && !trace.start.IsZero() {
+			config.metrics.histProxyConnectDuration.WithLabelValues(config.metrics.labelValues...).Observe(time.Since(trace.start).Seconds())
+			config.metrics.summaryProxyConnectDuration.WithLabelValues(config.metrics.labelValues...).Observe(time.Since(trace.start).Seconds())
+			applog.Infof("Proxy CONNECT to %s via %s: %v, status %v\n", connectReq.URL.Host, proxyURL.Host, time.Since(trace.start), connectRes.StatusCode)
+		}
+		return nil
+	}
+}
+
+// Add proxy CONNECT trace to the request context
+func withProxyConnectTrace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, proxyConnectTraceKey{}, &proxyConnectTrace{})
+}
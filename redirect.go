@@ -0,0 +1,91 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Same limit as the default one in net/http
+const defaultMaxRedirects = 10
+
+// Redirects of a single request, redirect policy finds it in the request context
+type redirectTrace struct {
+	firstStatus int
+	hops        int
+	hopStart    time.Time
+}
+
+type redirectTraceKey struct{}
+
+// Parse -follow-redirects value, which is true, false or max number of redirects
+func parseFollowRedirects(value string) (int, error) {
+	switch value {
+	case "true":
+		return defaultMaxRedirects, nil
+	case "false":
+		return 0, nil
+	}
+
+	redirects, err := strconv.Atoi(value)
+	if err != nil || redirects < 0 {
+		return 0, fmt.Errorf("expected true, false or a number of redirects >= 0, got %q", value)
+	}
+
+	return redirects, nil
+}
+
+// Attach redirect trace to the request context
+func withRedirectTrace(ctx context.Context, trace *redirectTrace) context.Context {
+	return context.WithValue(ctx, redirectTraceKey{}, trace)
+}
+
+// Redirect policy follows up to -follow-redirects redirects and times every hop.
+// When the limit is reached, the last redirect response is returned as is
+func redirectPolicy(config appConfig) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > config.maxRedirects {
+			return http.ErrUseLastResponse
+		}
+
+		if trace, ok := req.Context().Value(redirectTraceKey{}).(*redirectTrace); ok && req.Response != nil {
+			if trace.hops == 0 {
+				trace.firstStatus = req.Response.StatusCode
+			}
+			observeRedirectHop(config, trace)
+
+			localLabelValues := append(config.metrics.labelValues, fmt.Sprintf("%v", req.Response.StatusCode))
+			config.metrics.redirects.WithLabelValues(localLabelValues...).Inc()
+			applog.Infof("Redirect %v with status %v to %s", trace.hops, req.Response.StatusCode, req.URL)
+		}
+
+		return nil
+	}
+}
+
+// Observe the hop which is done now, the next one starts right away
+func observeRedirectHop(config appConfig, trace *redirectTrace) {
+	config.metrics.histRedirectHopDuration.WithLabelValues(config.metrics.labelValues...).Observe(time.Since(trace.hopStart).Seconds())
+	config.metrics.summaryRedirectHopDuration.WithLabelValues(config.metrics.labelValues...).Observe(time.Since(trace.hopStart).Seconds())
+	trace.hopStart = time.Now()
+	trace.hops++
+}
+
+// Observe the first and the final status of a request, and the final hop of redirected requests
+func observeRedirects(config appConfig, trace *redirectTrace, status int) {
+	firstStatus := status
+	if trace.hops > 0 {
+		firstStatus = trace.firstStatus
+		observeRedirectHop(config, trace)
+	}
+
+	localLabelValues := append(config.metrics.labelValues, fmt.Sprintf("%v", firstStatus))
+	config.metrics.requestsByFirstStatus.WithLabelValues(localLabelValues...).Inc()
+}
@@ -8,7 +8,9 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 )
@@ -25,6 +27,26 @@ type appReport struct {
 	DurationSeconds float64 `json:"DurationSeconds"`
 	MaxConcurrency  int     `json:"MaxConcurrency"`
 	RequestBodySize int64   `json:"RequestBodySize"`
+	Seed            int64   `json:"Seed"`
+
+	RequestPayloadType        string  `json:"RequestPayloadType"`
+	RequestBodyCompression    string  `json:"RequestBodyCompression"`
+	RequestCompressedBodySize int64   `json:"RequestCompressedBodySize"`
+	RequestsSentBytes         float64 `json:"RequestsSentBytes"`
+	RequestsSentRawBytes      float64 `json:"RequestsSentRawBytes"`
+
+	RequestBodySizeMean                     float64            `json:"RequestBodySizeMean"`
+	RequestBodySizeQuantiles                map[string]float64 `json:"RequestBodySizeQuantiles"`
+	UploadThroughputBytesPerSecondMean      float64            `json:"UploadThroughputBytesPerSecondMean"`
+	UploadThroughputBytesPerSecondQuantiles map[string]float64 `json:"UploadThroughputBytesPerSecondQuantiles"`
+
+	TimeToLastByteSecondsMean                 float64            `json:"TimeToLastByteSecondsMean"`
+	TimeToLastByteSecondsQuantiles            map[string]float64 `json:"TimeToLastByteSecondsQuantiles"`
+	DownloadThroughputBytesPerSecondMean      float64            `json:"DownloadThroughputBytesPerSecondMean"`
+	DownloadThroughputBytesPerSecondQuantiles map[string]float64 `json:"DownloadThroughputBytesPerSecondQuantiles"`
+	SSEEvents                                 float64            `json:"SSEEvents"`
+	SSEInterEventLatencySecondsMean           float64            `json:"SSEInterEventLatencySecondsMean"`
+	SSEInterEventLatencySecondsQuantiles      map[string]float64 `json:"SSEInterEventLatencySecondsQuantiles"`
 
 	RequestsCompleted float64 `json:"RequestsCompleted"`
 	RequestsSucceeded float64 `json:"RequestsSucceeded"`
@@ -36,9 +58,24 @@ type appReport struct {
 
 	HTTPResponseStatuses map[string]uint64 `json:"HTTPResponseStatuses"`
 
+	ResponseEncodings                          map[string]float64 `json:"ResponseEncodings"`
+	ResponseWireBytes                          float64            `json:"ResponseWireBytes"`
+	ResponseDecodedBytes                       float64            `json:"ResponseDecodedBytes"`
+	ResponseDecompressDurationSecondsMean      float64            `json:"ResponseDecompressDurationSecondsMean"`
+	ResponseDecompressDurationSecondsQuantiles map[string]float64 `json:"ResponseDecompressDurationSecondsQuantiles"`
+
+	HTTPFirstResponseStatuses           map[string]float64 `json:"HTTPFirstResponseStatuses"`
+	Redirects                           map[string]float64 `json:"Redirects"`
+	RedirectHopDurationSecondsMean      float64            `json:"RedirectHopDurationSecondsMean"`
+	RedirectHopDurationSecondsQuantiles map[string]float64 `json:"RedirectHopDurationSecondsQuantiles"`
+
 	FullRequestDurationSecondsMean      float64            `json:"FullRequestDurationSecondsMean"`
 	FullRequestDurationSecondsQuantiles map[string]float64 `json:"FullRequestDurationSecondsQuantiles"`
 
+	RequestsByAddress  map[string]float64 `json:"RequestsByAddress"`
+	RequestsBySourceIP map[string]float64 `json:"RequestsBySourceIP"`
+	ErrorsBySourceIP   map[string]float64 `json:"ErrorsBySourceIP"`
+
 	DNSRequests                 uint64             `json:"DNSRequests"`
 	DNSDurationSecondsMean      float64            `json:"DNSDurationSecondsMean"`
 	DNSDurationSecondsQuantiles map[string]float64 `json:"DNSDurationSecondsQuantiles"`
@@ -47,10 +84,39 @@ type appReport struct {
 	TCPDurationSecondsMean      float64            `json:"TCPDurationSecondsMean"`
 	TCPDurationSecondsQuantiles map[string]float64 `json:"TCPDurationSecondsQuantiles"`
 
+	NewConnectionsRate                     float64            `json:"NewConnectionsRate"`
+	ConnectionsRecycled                    map[string]float64 `json:"ConnectionsRecycled"`
+	ConnectionsNew                         float64            `json:"ConnectionsNew"`
+	ConnectionsReused                      float64            `json:"ConnectionsReused"`
+	ConnectionReuseRatio                   float64            `json:"ConnectionReuseRatio"`
+	ConnectionIdleDurationSecondsMean      float64            `json:"ConnectionIdleDurationSecondsMean"`
+	ConnectionIdleDurationSecondsQuantiles map[string]float64 `json:"ConnectionIdleDurationSecondsQuantiles"`
+
+	ProxyConnects                        uint64             `json:"ProxyConnects"`
+	ProxyConnectDurationSecondsMean      float64            `json:"ProxyConnectDurationSecondsMean"`
+	ProxyConnectDurationSecondsQuantiles map[string]float64 `json:"ProxyConnectDurationSecondsQuantiles"`
+
 	TLSHandshakes               uint64             `json:"TLSHandshakes"`
 	TLSDurationSecondsMean      float64            `json:"TLSDurationSecondsMean"`
 	TLSDurationSecondsQuantiles map[string]float64 `json:"TLSDurationSecondsQuantiles"`
 
+	TLSVersions     map[string]float64 `json:"TLSVersions"`
+	TLSCipherSuites map[string]float64 `json:"TLSCipherSuites"`
+	TLSALPN         map[string]float64 `json:"TLSALPN"`
+
+	TLSFullHandshakes                        float64            `json:"TLSFullHandshakes"`
+	TLSFullHandshakeDurationSecondsMean      float64            `json:"TLSFullHandshakeDurationSecondsMean"`
+	TLSFullHandshakeDurationSecondsQuantiles map[string]float64 `json:"TLSFullHandshakeDurationSecondsQuantiles"`
+
+	TLSResumedHandshakes                        float64            `json:"TLSResumedHandshakes"`
+	TLSResumedHandshakeDurationSecondsMean      float64            `json:"TLSResumedHandshakeDurationSecondsMean"`
+	TLSResumedHandshakeDurationSecondsQuantiles map[string]float64 `json:"TLSResumedHandshakeDurationSecondsQuantiles"`
+
+	TLSSessionResumptionRatio      float64            `json:"TLSSessionResumptionRatio"`
+	TLSCertificateExpiresInSeconds map[string]float64 `json:"TLSCertificateExpiresInSeconds"`
+
+	QUICZeroRTTConnections float64 `json:"QUICZeroRTTConnections"`
+
 	HTTPWriteRequestBodyDurationSecondsMean      float64            `json:"HTTPWriteRequestBodyDurationSecondsMean"`
 	HTTPWriteRequestBodyDurationSecondsQuantiles map[string]float64 `json:"HTTPWriteRequestBodyDurationSecondsQuantiles"`
 
@@ -59,6 +125,23 @@ type appReport struct {
 
 	HTTPResponseDurationSecondsMean      float64            `json:"HTTPResponseDurationSecondsMean"`
 	HTTPResponseDurationSecondsQuantiles map[string]float64 `json:"HTTPResponseDurationSecondsQuantiles"`
+
+	RequestsByName   map[string]requestReport `json:"RequestsByName"`
+	RequestsByTarget map[string]requestReport `json:"RequestsByTarget"`
+
+	ReplayOriginalRate                float64            `json:"ReplayOriginalRate"`
+	ReplayTargetRate                  float64            `json:"ReplayTargetRate"`
+	ReplayFidelity                    float64            `json:"ReplayFidelity"`
+	ReplayScheduleLagSecondsMean      float64            `json:"ReplayScheduleLagSecondsMean"`
+	ReplayScheduleLagSecondsQuantiles map[string]float64 `json:"ReplayScheduleLagSecondsQuantiles"`
+
+	AuthTokenFetches                       float64            `json:"AuthTokenFetches"`
+	AuthTokenFetchErrors                   float64            `json:"AuthTokenFetchErrors"`
+	AuthTokenFetchDurationSecondsMean      float64            `json:"AuthTokenFetchDurationSecondsMean"`
+	AuthTokenFetchDurationSecondsQuantiles map[string]float64 `json:"AuthTokenFetchDurationSecondsQuantiles"`
+
+	SessionExtractedVariables  map[string]float64 `json:"SessionExtractedVariables"`
+	SessionUnresolvedVariables map[string]float64 `json:"SessionUnresolvedVariables"`
 }
 
 // Get report
@@ -86,12 +169,62 @@ func collectReport(config appConfig, duration float64) appReport {
 		statusLabels[k] = v
 	}
 
-	report.Target = config.sendEndpoint
+	report.Target = reportTarget(config)
 	report.SendMode = config.sendMode
-	report.SendMethod = config.sendMethod
+	report.SendMethod = reportMethod(config)
 	report.DurationSeconds = duration
 	report.MaxConcurrency = config.workers
 	report.RequestBodySize = int64(len(config.sendPayload))
+	report.RequestPayloadType = config.sendPayloadType
+	report.Seed = config.seed
+	if len(config.form) > 0 {
+		report.RequestBodySize = formBodySize(config)
+	}
+
+	// Random bodies of different sizes
+	if config.randomBody != nil {
+		if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_requests_body_size_bytes", config.metrics.labels); err == nil {
+			report.RequestBodySize = int64(mean)
+			report.RequestBodySizeMean = mean
+			report.RequestBodySizeQuantiles = jsonizeFloatMap(quantiles)
+		}
+	}
+
+	// Streamed request bodies
+	if config.sendStream {
+		report.RequestBodySize = config.sendStreamSize
+
+		if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_requests_upload_throughput_bytes_per_second", config.metrics.labels); err == nil {
+			report.UploadThroughputBytesPerSecondMean = mean
+			report.UploadThroughputBytesPerSecondQuantiles = jsonizeFloatMap(quantiles)
+		}
+	}
+
+	// Streamed responses
+	if config.readStream {
+		if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_response_time_to_last_byte_seconds", config.metrics.labels); err == nil {
+			report.TimeToLastByteSecondsMean = mean
+			report.TimeToLastByteSecondsQuantiles = jsonizeFloatMap(quantiles)
+		}
+		if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_response_download_throughput_bytes_per_second", config.metrics.labels); err == nil {
+			report.DownloadThroughputBytesPerSecondMean = mean
+			report.DownloadThroughputBytesPerSecondQuantiles = jsonizeFloatMap(quantiles)
+		}
+
+		report.SSEEvents, _ = getCounter(config.metrics.sseEvents, config.metrics.labelValues...)
+		if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_sse_inter_event_latency_seconds", config.metrics.labels); err == nil {
+			report.SSEInterEventLatencySecondsMean = mean
+			report.SSEInterEventLatencySecondsQuantiles = jsonizeFloatMap(quantiles)
+		}
+	}
+
+	// Compressed request bodies, sent bytes are compressed and raw bytes are before compression
+	if config.sendCompress != "" {
+		report.RequestBodyCompression = config.sendCompress
+		report.RequestCompressedBodySize = int64(len(config.sendPayloadCompressed))
+		report.RequestsSentBytes, _ = getCounter(config.metrics.requestsSendBytesSum, config.metrics.labelValues...)
+		report.RequestsSentRawBytes, _ = getCounter(config.metrics.requestsSendRawBytesSum, config.metrics.labelValues...)
+	}
 
 	// Main results
 	report.RequestsCompleted, _ = getCounter(config.metrics.requestsSendCount, config.metrics.labelValues...)
@@ -102,6 +235,31 @@ func collectReport(config appConfig, duration float64) appReport {
 		report.OverallRequestsRate = requests / duration
 	}
 
+	// Auth token requests
+	if config.authProvider != nil {
+		report.AuthTokenFetches, _ = getCounter(config.metrics.authTokenFetches, config.metrics.labelValues...)
+		report.AuthTokenFetchErrors, _ = getCounter(config.metrics.authTokenFetchErrors, config.metrics.labelValues...)
+
+		if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_auth_token_fetch_duration_seconds", config.metrics.labels); err == nil {
+			report.AuthTokenFetchDurationSecondsMean = mean
+			report.AuthTokenFetchDurationSecondsQuantiles = jsonizeFloatMap(quantiles)
+		}
+	}
+
+	// Replay fidelity, how close we got to the desired rate
+	if config.replay {
+		report.ReplayOriginalRate = requestsOriginalRate(config.requests)
+		report.ReplayTargetRate = replayTargetRate(config)
+		if report.ReplayTargetRate > 0 {
+			report.ReplayFidelity = report.OverallRequestsRate / report.ReplayTargetRate
+		}
+
+		if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_replay_schedule_lag_seconds", config.metrics.labels); err == nil {
+			report.ReplayScheduleLagSecondsMean = mean
+			report.ReplayScheduleLagSecondsQuantiles = jsonizeFloatMap(quantiles)
+		}
+	}
+
 	// Time per request and transfer rates
 	if _, seconds, err := getCountSumFromSummary(registry, "minigun_requests_duration_seconds", config.metrics.labels); err == nil {
 
@@ -111,6 +269,16 @@ func collectReport(config appConfig, duration float64) appReport {
 		bytes, _ = getCounter(config.metrics.responseBytesSum, config.metrics.labelValues...)
 		report.OverallReceivedBytesPerSecond = bytes / seconds
 
+		report.RequestsByAddress, _ = getMetricValuesByLabel(registry, "minigun_requests_by_address_total", "address")
+		report.RequestsBySourceIP, _ = getMetricValuesByLabel(registry, "minigun_requests_by_source_ip_total", "source_ip")
+		report.ErrorsBySourceIP, _ = getMetricValuesByLabel(registry, "minigun_requests_errors_by_source_ip_total", "source_ip")
+
+		// Session variables
+		if len(config.extractRules) > 0 {
+			report.SessionExtractedVariables, _ = getMetricValuesByLabel(registry, "minigun_session_extracted_variables_total", "variable")
+			report.SessionUnresolvedVariables, _ = getMetricValuesByLabel(registry, "minigun_session_unresolved_variables_total", "variable")
+		}
+
 		// DNS info
 		if requests, _, mean, quantiles, err := getSummaryValues(registry, "minigun_httptrace_dns_duration_seconds", config.metrics.labels); err == nil && requests > 0 {
 			report.DNSRequests = requests
@@ -121,15 +289,73 @@ func collectReport(config appConfig, duration float64) appReport {
 		// TCP connection info
 		if requests, _, mean, quantiles, err := getSummaryValues(registry, "minigun_httptrace_connect_duration_seconds", config.metrics.labels); err == nil {
 			report.TCPConnections = requests
+			report.NewConnectionsRate = float64(requests) / duration
 			report.TCPDurationSecondsMean = mean
 			report.TCPDurationSecondsQuantiles = jsonizeFloatMap(quantiles)
 		}
 
+		// Connection reuse info
+		report.ConnectionsRecycled, _ = getMetricValuesByLabel(registry, "minigun_runtime_recycled_connections_total", "reason")
+		if connections, err := getMetricValuesByLabel(registry, "minigun_httptrace_got_connections_total", "reused"); err == nil {
+			report.ConnectionsNew = connections["false"]
+			report.ConnectionsReused = connections["true"]
+			if total := connections["false"] + connections["true"]; total > 0 {
+				report.ConnectionReuseRatio = connections["true"] / total
+			}
+		}
+
+		if requests, _, mean, quantiles, err := getSummaryValues(registry, "minigun_httptrace_connection_idle_duration_seconds", config.metrics.labels); err == nil && requests > 0 {
+			report.ConnectionIdleDurationSecondsMean = mean
+			report.ConnectionIdleDurationSecondsQuantiles = jsonizeFloatMap(quantiles)
+		}
+
+		// Proxy info
+		if requests, _, mean, quantiles, err := getSummaryValues(registry, "minigun_httptrace_proxy_connect_duration_seconds", config.metrics.labels); err == nil && requests > 0 {
+			report.ProxyConnects = requests
+			report.ProxyConnectDurationSecondsMean = mean
+			report.ProxyConnectDurationSecondsQuantiles = jsonizeFloatMap(quantiles)
+		}
+
 		// TLS info
 		if requests, _, mean, quantiles, err := getSummaryValues(registry, "minigun_httptrace_tls_handshake_duration_seconds", config.metrics.labels); err == nil && requests > 0 {
 			report.TLSHandshakes = requests
 			report.TLSDurationSecondsMean = mean
 			report.TLSDurationSecondsQuantiles = jsonizeFloatMap(quantiles)
+			report.TLSVersions, _ = getMetricValuesByLabel(registry, "minigun_tls_connections_total", "tls_version")
+			report.TLSCipherSuites, _ = getMetricValuesByLabel(registry, "minigun_tls_connections_total", "tls_cipher")
+			report.TLSALPN, _ = getMetricValuesByLabel(registry, "minigun_tls_connections_total", "tls_alpn")
+
+			// Handshake types
+			if handshakes, err := getMetricValuesByLabel(registry, "minigun_tls_connections_total", "tls_handshake"); err == nil {
+				report.TLSFullHandshakes = handshakes["full"]
+				report.TLSResumedHandshakes = handshakes["resumed"]
+				if total := handshakes["full"] + handshakes["resumed"]; total > 0 {
+					report.TLSSessionResumptionRatio = handshakes["resumed"] / total
+				}
+			}
+
+			if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_tls_handshake_duration_seconds", tlsHandshakeLabels(config, "full")); err == nil {
+				report.TLSFullHandshakeDurationSecondsMean = mean
+				report.TLSFullHandshakeDurationSecondsQuantiles = jsonizeFloatMap(quantiles)
+			}
+
+			if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_tls_handshake_duration_seconds", tlsHandshakeLabels(config, "resumed")); err == nil {
+				report.TLSResumedHandshakeDurationSecondsMean = mean
+				report.TLSResumedHandshakeDurationSecondsQuantiles = jsonizeFloatMap(quantiles)
+			}
+
+			// Certificate expiry
+			if expiry, err := getMetricValuesByLabel(registry, "minigun_tls_certificate_expiry_timestamp_seconds", "tls_subject"); err == nil {
+				report.TLSCertificateExpiresInSeconds = make(map[string]float64)
+				for subject, timestamp := range expiry {
+					report.TLSCertificateExpiresInSeconds[subject] = time.Until(time.Unix(int64(timestamp), 0)).Seconds()
+				}
+			}
+		}
+
+		// QUIC info
+		if config.sendMode == "http3" {
+			report.QUICZeroRTTConnections, _ = getCounter(config.metrics.quicZeroRTTConnections, config.metrics.labelValues...)
 		}
 
 		// Get HTTP statuses info
@@ -145,6 +371,29 @@ func collectReport(config appConfig, duration float64) appReport {
 			}
 		}
 
+		// Compressed responses
+		if reportResponseEncodings(config) {
+			report.ResponseEncodings, _ = getMetricValuesByLabel(registry, "minigun_response_by_encoding_total", "encoding")
+			report.ResponseWireBytes, _ = getCounter(config.metrics.responseBytesSum, config.metrics.labelValues...)
+			report.ResponseDecodedBytes, _ = getCounter(config.metrics.responseDecodedBytesSum, config.metrics.labelValues...)
+
+			if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_response_decompress_duration_seconds", config.metrics.labels); err == nil {
+				report.ResponseDecompressDurationSecondsMean = mean
+				report.ResponseDecompressDurationSecondsQuantiles = jsonizeFloatMap(quantiles)
+			}
+		}
+
+		// Statuses before redirects and every redirect hop, if there were any redirects
+		if redirects, err := getMetricValuesByLabel(registry, "minigun_redirects_total", "status"); err == nil && len(redirects) > 0 {
+			report.Redirects = redirects
+			report.HTTPFirstResponseStatuses, _ = getMetricValuesByLabel(registry, "minigun_requests_by_first_status_total", "status")
+
+			if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_redirects_hop_duration_seconds", config.metrics.labels); err == nil {
+				report.RedirectHopDurationSecondsMean = mean
+				report.RedirectHopDurationSecondsQuantiles = jsonizeFloatMap(quantiles)
+			}
+		}
+
 		// Get more quantiles
 		if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_requests_duration_seconds", config.metrics.labels); err == nil {
 			report.FullRequestDurationSecondsMean = mean
@@ -165,11 +414,67 @@ func collectReport(config appConfig, duration float64) appReport {
 			report.HTTPTimeToFirstByteSecondsMean = mean
 			report.HTTPTimeToFirstByteSecondsQuantiles = jsonizeFloatMap(quantiles)
 		}
+
+		if len(config.requests) > 0 {
+			report.RequestsByName = collectRequestsReport(config)
+		}
+
+		if config.targets != nil {
+			report.RequestsByTarget = collectTargetsReport(config)
+		}
 	}
 
 	return report
 }
 
+// Benchmark target, or source of the request list
+func reportTarget(config appConfig) string {
+	if config.targets != nil {
+		return fmt.Sprintf("%v targets (%s)", len(config.targets.targets), config.targets.strategy)
+	}
+	if config.sendEndpoint == "" && config.requestsSource != "" {
+		return config.requestsSource
+	}
+
+	return config.sendEndpoint
+}
+
+// Request lists have method of every request
+func reportMethod(config appConfig) string {
+	if len(config.requests) > 0 {
+		return "per request"
+	}
+
+	return config.sendMethod
+}
+
+// Gzip is asked for by default, so compression is reported if it's asked for explicitly or any response was encoded
+func reportResponseEncodings(config appConfig) bool {
+	if config.acceptEncoding != "" {
+		return true
+	}
+
+	encodings, _ := getMetricValuesByLabel(registry, "minigun_response_by_encoding_total", "encoding")
+	for encoding, count := range encodings {
+		if encoding != "identity" && count > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Copy of main labels map with TLS handshake type label
+func tlsHandshakeLabels(config appConfig, handshakeType string) map[string]string {
+	labels := make(map[string]string, 0)
+	for k, v := range config.metrics.labels {
+		labels[k] = v
+	}
+	labels["tls_handshake"] = handshakeType
+
+	return labels
+}
+
 // Helper func which converts float64 map keys to string, JSON supports only strings as map keys
 func jsonizeFloatMap(in map[float64]float64) map[string]float64 {
 	result := make(map[string]float64)
@@ -250,12 +555,25 @@ func reportTextOld(config appConfig, duration float64) string {
 	fmt.Println()
 
 	// Main benchmark info
-	outMatrix = append(outMatrix, printRow{"Target:", config.sendEndpoint})
+	outMatrix = append(outMatrix, printRow{"Target:", reportTarget(config)})
 	outMatrix = append(outMatrix, printRow{"Mode:", config.sendMode})
-	outMatrix = append(outMatrix, printRow{"Method:", config.sendMethod})
+	outMatrix = append(outMatrix, printRow{"Method:", reportMethod(config)})
 	outMatrix = append(outMatrix, printRow{"Duration:", fmt.Sprintf("%.2f seconds", duration)})
 	outMatrix = append(outMatrix, printRow{"Max concurrency:", fmt.Sprintf("%v", config.workers)})
-	outMatrix = append(outMatrix, printRow{"Request body size:", fmt.Sprintf("%v", humanizeBytes(int64(len(config.sendPayload)), false))})
+	if config.sendStream {
+		outMatrix = append(outMatrix, printRow{"Request body size:", fmt.Sprintf("%v (streamed)", humanizeBytes(config.sendStreamSize, false))})
+	} else if config.randomBody != nil {
+		outMatrix = append(outMatrix, printRow{"Request body size:", fmt.Sprintf("%v (random %s)", config.randomBody.sizes, config.randomBody.mode)})
+	} else if len(config.form) > 0 {
+		outMatrix = append(outMatrix, printRow{"Request body size:", fmt.Sprintf("%v (%s form)", humanizeBytes(formBodySize(config), false), config.formType)})
+	} else if config.sendCompress != "" && len(config.sendPayloadCompressed) > 0 {
+		outMatrix = append(outMatrix, printRow{"Request body size:", fmt.Sprintf("%v (%v compressed with %s)",
+			humanizeBytes(int64(len(config.sendPayload)), false), humanizeBytes(int64(len(config.sendPayloadCompressed)), false), config.sendCompress)})
+	} else if encodedPayloadTypes[config.sendPayloadType] && len(config.sendPayload) > 0 {
+		outMatrix = append(outMatrix, printRow{"Request body size:", fmt.Sprintf("%v (%s encoded)", humanizeBytes(int64(len(config.sendPayload)), false), config.sendPayloadType)})
+	} else {
+		outMatrix = append(outMatrix, printRow{"Request body size:", fmt.Sprintf("%v", humanizeBytes(int64(len(config.sendPayload)), false))})
+	}
 
 	// Separator
 	if reportBorders {
@@ -267,10 +585,27 @@ func reportTextOld(config appConfig, duration float64) string {
 	outMatrix = addCounterToReport(outMatrix, "Succeeded requests:", config.metrics.responseBytesCount, config.metrics.labelValues...)
 	outMatrix = addCounterToReport(outMatrix, "Failed requests:", config.metrics.requestsSendErrors, config.metrics.labelValues...)
 
+	// Auth token requests are not counted as benchmarked requests
+	if config.authProvider != nil {
+		fetches, _ := getCounter(config.metrics.authTokenFetches, config.metrics.labelValues...)
+		errors, _ := getCounter(config.metrics.authTokenFetchErrors, config.metrics.labelValues...)
+		outMatrix = append(outMatrix, printRow{"Auth token fetches:", fmt.Sprintf("%v (%v failed)", fetches, errors)})
+	}
+
 	if requests, err := getCounter(config.metrics.requestsSendCount, config.metrics.labelValues...); err == nil {
 		rate := requests / duration
 		outMatrix = append(outMatrix, printRow{"Requests per second:", fmt.Sprintf("%.2f (mean, across all concurrent requests)", rate)})
 
+		// Replay fidelity, how close we got to the desired rate
+		if config.replay {
+			if originalRate := requestsOriginalRate(config.requests); originalRate > 0 {
+				outMatrix = append(outMatrix, printRow{"Original requests per second:", fmt.Sprintf("%.2f (recorded)", originalRate)})
+			}
+			if targetRate := replayTargetRate(config); targetRate > 0 {
+				outMatrix = append(outMatrix, printRow{"Replay fidelity:", fmt.Sprintf("%.2f%% (%.2f of %.2f requests per second)", rate/targetRate*100, rate, targetRate)})
+			}
+		}
+
 		if config.abTimePerRequest {
 			// Mean request time
 			if _, _, mean, _, err := getSummaryValues(registry, "minigun_requests_duration_seconds", config.metrics.labels); err == nil {
@@ -297,6 +632,73 @@ func reportTextOld(config appConfig, duration float64) string {
 			outMatrix = append(outMatrix, printRow{"Transfer rate (HTTP Message Body)", tmpPrint})
 		}
 
+		// Body sizes of random bodies, per request
+		if config.randomBody != nil {
+			if count, _, mean, quantiles, err := getSummaryValues(registry, "minigun_requests_body_size_bytes", config.metrics.labels); err == nil && count > 0 {
+				outMatrix = append(outMatrix, printRow{"Request body sizes", fmt.Sprintf("%v (mean)\n%v (median)\n%v (P90)\n%v (P99)",
+					humanizeBytes(int64(mean), false), humanizeBytes(int64(quantiles[0.5]), false), humanizeBytes(int64(quantiles[0.9]), false), humanizeBytes(int64(quantiles[0.99]), false))})
+			}
+		}
+
+		// Upload throughput of streamed bodies, per request
+		if config.sendStream {
+			if count, _, mean, quantiles, err := getSummaryValues(registry, "minigun_requests_upload_throughput_bytes_per_second", config.metrics.labels); err == nil && count > 0 {
+				outMatrix = append(outMatrix, printRow{"Upload throughput", fmt.Sprintf("%v (mean)\n%v (median)\n%v (P90)\n%v (P99)",
+					humanizeBytes(int64(mean), true), humanizeBytes(int64(quantiles[0.5]), true), humanizeBytes(int64(quantiles[0.9]), true), humanizeBytes(int64(quantiles[0.99]), true))})
+			}
+		}
+
+		// Download throughput of streamed responses, per response
+		if config.readStream {
+			if count, _, mean, quantiles, err := getSummaryValues(registry, "minigun_response_download_throughput_bytes_per_second", config.metrics.labels); err == nil && count > 0 {
+				outMatrix = append(outMatrix, printRow{"Download throughput", fmt.Sprintf("%v (mean)\n%v (median)\n%v (P90)\n%v (P99)",
+					humanizeBytes(int64(mean), true), humanizeBytes(int64(quantiles[0.5]), true), humanizeBytes(int64(quantiles[0.9]), true), humanizeBytes(int64(quantiles[0.99]), true))})
+			}
+
+			if events, err := getCounter(config.metrics.sseEvents, config.metrics.labelValues...); err == nil && events > 0 {
+				outMatrix = append(outMatrix, printRow{"Server-sent events", fmt.Sprintf("%v", events)})
+			}
+		}
+
+		// Compressed requests, transfer rate above is for compressed bytes
+		if config.sendCompress != "" {
+			sent, _ := getCounter(config.metrics.requestsSendBytesSum, config.metrics.labelValues...)
+			raw, _ := getCounter(config.metrics.requestsSendRawBytesSum, config.metrics.labelValues...)
+			if sent > 0 {
+				outMatrix = append(outMatrix, printRow{"Request bytes", fmt.Sprintf("%v sent, %v before %s compression (%.2fx)",
+					humanizeBytes(int64(sent), false), humanizeBytes(int64(raw), false), config.sendCompress, raw/sent)})
+			}
+		}
+
+		// Compressed responses, transfer rate above is for bytes on the wire
+		if reportResponseEncodings(config) {
+			outMatrix = addCounterValuesToReport(outMatrix, "Response encodings", registry, "minigun_response_by_encoding_total", "encoding")
+
+			wire, _ := getCounter(config.metrics.responseBytesSum, config.metrics.labelValues...)
+			decoded, _ := getCounter(config.metrics.responseDecodedBytesSum, config.metrics.labelValues...)
+			if wire > 0 {
+				outMatrix = append(outMatrix, printRow{"Response bytes", fmt.Sprintf("%v on the wire, %v decoded (%.2fx)",
+					humanizeBytes(int64(wire), false), humanizeBytes(int64(decoded), false), decoded/wire)})
+			}
+		}
+
+		// Requests per remote address, only interesting if there're many of them or we resolve them ourselves
+		if addresses, err := getMetricValuesByLabel(registry, "minigun_requests_by_address_total", "address"); err == nil && (len(addresses) > 1 || config.dnsResolver != nil) {
+			outMatrix = addCounterValuesToReport(outMatrix, "Requests per address", registry, "minigun_requests_by_address_total", "address")
+		}
+
+		// Requests and errors per source IP
+		if len(config.sourceIPs) > 0 {
+			outMatrix = addCounterValuesToReport(outMatrix, "Requests per source IP", registry, "minigun_requests_by_source_ip_total", "source_ip")
+			outMatrix = addCounterValuesToReport(outMatrix, "Errors per source IP", registry, "minigun_requests_errors_by_source_ip_total", "source_ip")
+		}
+
+		// Session variables, unresolved ones mean extraction doesn't work as expected
+		if len(config.extractRules) > 0 {
+			outMatrix = addCounterValuesToReport(outMatrix, "Extracted variables", registry, "minigun_session_extracted_variables_total", "variable")
+			outMatrix = addCounterValuesToReport(outMatrix, "Unresolved variables", registry, "minigun_session_unresolved_variables_total", "variable")
+		}
+
 		// DNS info
 		if requests, _, err := getCountSumFromSummary(registry, "minigun_httptrace_dns_duration_seconds", config.metrics.labels); err == nil && requests > 0 {
 			outMatrix = append(outMatrix, printRow{"DNS queries", fmt.Sprintf("%v", requests)})
@@ -305,11 +707,56 @@ func reportTextOld(config appConfig, duration float64) string {
 		// TCP connection info
 		if requests, _, err := getCountSumFromSummary(registry, "minigun_httptrace_connect_duration_seconds", config.metrics.labels); err == nil {
 			outMatrix = append(outMatrix, printRow{"TCP connections", fmt.Sprintf("%v", requests)})
+
+			if useConnChurn(config) {
+				outMatrix = append(outMatrix, printRow{"New connections per second", fmt.Sprintf("%.2f (mean, across all concurrent requests)", float64(requests)/duration)})
+				outMatrix = addCounterValuesToReport(outMatrix, "Recycled connections", registry, "minigun_runtime_recycled_connections_total", "reason")
+			}
+		}
+
+		// Connection reuse info
+		if connections, err := getMetricValuesByLabel(registry, "minigun_httptrace_got_connections_total", "reused"); err == nil {
+			if total := connections["false"] + connections["true"]; total > 0 {
+				outMatrix = append(outMatrix, printRow{"Connection reuse ratio", fmt.Sprintf("%.2f%% (%v new, %v reused)", connections["true"]/total*100, connections["false"], connections["true"])})
+			}
+		}
+
+		// Proxy info
+		if requests, _, err := getCountSumFromSummary(registry, "minigun_httptrace_proxy_connect_duration_seconds", config.metrics.labels); err == nil && requests > 0 {
+			outMatrix = append(outMatrix, printRow{"Proxy CONNECT requests", fmt.Sprintf("%v", requests)})
 		}
 
 		// TLS info
 		if requests, _, err := getCountSumFromSummary(registry, "minigun_httptrace_tls_handshake_duration_seconds", config.metrics.labels); err == nil && requests > 0 {
 			outMatrix = append(outMatrix, printRow{"TLS Handshakes", fmt.Sprintf("%v", requests)})
+			outMatrix = addCounterValuesToReport(outMatrix, "TLS versions", registry, "minigun_tls_connections_total", "tls_version")
+			outMatrix = addCounterValuesToReport(outMatrix, "TLS cipher suites", registry, "minigun_tls_connections_total", "tls_cipher")
+			outMatrix = addCounterValuesToReport(outMatrix, "TLS ALPN protocols", registry, "minigun_tls_connections_total", "tls_alpn")
+			outMatrix = addCounterValuesToReport(outMatrix, "TLS handshake types", registry, "minigun_tls_connections_total", "tls_handshake")
+
+			if handshakes, err := getMetricValuesByLabel(registry, "minigun_tls_connections_total", "tls_handshake"); err == nil {
+				if total := handshakes["full"] + handshakes["resumed"]; total > 0 {
+					outMatrix = append(outMatrix, printRow{"TLS session resumption ratio", fmt.Sprintf("%.2f%%", handshakes["resumed"]/total*100)})
+				}
+			}
+
+			if expiry, err := getMetricValuesByLabel(registry, "minigun_tls_certificate_expiry_timestamp_seconds", "tls_subject"); err == nil {
+				expiryReport := make([]string, 0)
+				for subject, timestamp := range expiry {
+					expiresAt := time.Unix(int64(timestamp), 0)
+					expiryReport = append(expiryReport, fmt.Sprintf("%s: %s (in %.0f days)", subject, expiresAt.UTC().Format(time.RFC3339), time.Until(expiresAt).Hours()/24))
+				}
+				sort.Strings(expiryReport)
+
+				if len(expiryReport) > 0 {
+					outMatrix = append(outMatrix, printRow{"TLS certificate expiry", strings.Join(expiryReport, "\n")})
+				}
+			}
+		}
+
+		// QUIC info
+		if config.sendMode == "http3" && config.http3ZeroRTT {
+			outMatrix = addCounterToReport(outMatrix, "QUIC 0-RTT connections", config.metrics.quicZeroRTTConnections, config.metrics.labelValues...)
 		}
 
 		// Get HTTP statuses info
@@ -328,6 +775,12 @@ func reportTextOld(config appConfig, duration float64) string {
 				outMatrix = append(outMatrix, printRow{"HTTP status codes", fmt.Sprintf("%s", strings.Join(statusReport, " "))})
 			}
 		}
+
+		// Statuses before redirects, if there were any redirects
+		if redirects, err := getMetricValuesByLabel(registry, "minigun_redirects_total", "status"); err == nil && len(redirects) > 0 {
+			outMatrix = addCounterValuesToReport(outMatrix, "First HTTP status codes", registry, "minigun_requests_by_first_status_total", "status")
+			outMatrix = addCounterValuesToReport(outMatrix, "Redirects", registry, "minigun_redirects_total", "status")
+		}
 	}
 
 	// Add the first table to the report
@@ -340,13 +793,29 @@ func reportTextOld(config appConfig, duration float64) string {
 	outLatencies = addSummaryToReport(outLatencies, "Full request duration", registry, "minigun_requests_duration_seconds", config.metrics.labels)
 	outLatencies = addSummaryToReport(outLatencies, "DNS request duration", registry, "minigun_httptrace_dns_duration_seconds", config.metrics.labels)
 	outLatencies = addSummaryToReport(outLatencies, "TCP connection duration", registry, "minigun_httptrace_connect_duration_seconds", config.metrics.labels)
+	outLatencies = addSummaryToReport(outLatencies, "Connection idle time", registry, "minigun_httptrace_connection_idle_duration_seconds", config.metrics.labels)
+	outLatencies = addSummaryToReport(outLatencies, "Proxy CONNECT duration", registry, "minigun_httptrace_proxy_connect_duration_seconds", config.metrics.labels)
 	outLatencies = addSummaryToReport(outLatencies, "TLS handshake duration", registry, "minigun_httptrace_tls_handshake_duration_seconds", config.metrics.labels)
+	outLatencies = addSummaryToReport(outLatencies, "TLS full handshake", registry, "minigun_tls_handshake_duration_seconds", tlsHandshakeLabels(config, "full"))
+	outLatencies = addSummaryToReport(outLatencies, "TLS resumed handshake", registry, "minigun_tls_handshake_duration_seconds", tlsHandshakeLabels(config, "resumed"))
 	outLatencies = addSummaryToReport(outLatencies, "HTTP write request body", registry, "minigun_httptrace_write_request_body_duration_seconds", config.metrics.labels)
 	outLatencies = addSummaryToReport(outLatencies, "HTTP time to first byte", registry, "minigun_httptrace_time_to_first_byte_seconds", config.metrics.labels)
 	outLatencies = addSummaryToReport(outLatencies, "HTTP response duration", registry, "minigun_response_duration_seconds", config.metrics.labels)
+	outLatencies = addSummaryToReport(outLatencies, "HTTP time to last byte", registry, "minigun_response_time_to_last_byte_seconds", config.metrics.labels)
+	outLatencies = addSummaryToReport(outLatencies, "SSE inter-event latency", registry, "minigun_sse_inter_event_latency_seconds", config.metrics.labels)
+	outLatencies = addSummaryToReport(outLatencies, "Response decompression", registry, "minigun_response_decompress_duration_seconds", config.metrics.labels)
+	outLatencies = addSummaryToReport(outLatencies, "Redirect hop", registry, "minigun_redirects_hop_duration_seconds", config.metrics.labels)
+	outLatencies = addSummaryToReport(outLatencies, "Auth token fetch", registry, "minigun_auth_token_fetch_duration_seconds", config.metrics.labels)
+	outLatencies = addSummaryToReport(outLatencies, "Replay schedule lag", registry, "minigun_replay_schedule_lag_seconds", config.metrics.labels)
 
 	// Add the second table to the report
 	report += formatPrintMatrix(outHeader, outLatencies, true, reportBorders)
 
+	// Per request table for request lists
+	report += reportRequestsText(config)
+
+	// Per target table for multiple targets
+	report += reportTargetsText(config)
+
 	return report
 }
@@ -0,0 +1,269 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// A single request from a request list, like imported HAR file entries
+type requestSpec struct {
+	name    string
+	method  string
+	url     string
+	headers httpHeaders
+	payload []byte
+
+	// Form fields, body is built per request
+	form     formFields
+	formType string
+
+	// Payload compressed once at startup with -send-compress
+	compressedPayload []byte
+
+	// Offset from the beginning of the recorded session, used for timed replays
+	offset time.Duration
+}
+
+// Per request report
+type requestReport struct {
+	Count                    float64            `json:"Count"`
+	Errors                   float64            `json:"Errors"`
+	DurationSecondsMean      float64            `json:"DurationSecondsMean"`
+	DurationSecondsQuantiles map[string]float64 `json:"DurationSecondsQuantiles"`
+}
+
+// Make config for sending this request. Headers from CLI args take precedence over request headers
+func (r *requestSpec) apply(config appConfig) appConfig {
+	config.sendMethod = r.method
+	config.sendEndpoint = r.url
+	config.sendPayload = r.payload
+	config.sendPayloadCompressed = r.compressedPayload
+	config.form, config.formType = r.form, r.formType
+
+	headers := make(httpHeaders)
+	for key, value := range r.headers {
+		headers[http.CanonicalHeaderKey(key)] = value
+	}
+	for key, value := range config.sendHTTPHeaders {
+		headers[http.CanonicalHeaderKey(key)] = value
+	}
+	config.sendHTTPHeaders = headers
+
+	return config
+}
+
+// Replay requests preserving their relative timing, sped up by the speed factor. Starts over when done.
+// Rate is still limited by -fire-rate, if set
+func fireReplay(ctx context.Context, config appConfig, comm *chan message) {
+	var minInterval time.Duration
+	var last time.Time
+
+	if config.fireRate > 0 {
+		minInterval = time.Second / time.Duration(config.fireRate)
+	}
+
+	applog.Infof("Replaying %v requests, speed: %v", len(config.requests), config.replaySpeed)
+
+	// Recording has no gap between its last and first requests, so replay starts over after the mean gap
+	span := config.requests[len(config.requests)-1].offset
+	restart := time.Duration(float64(span+requestsMeanGap(config.requests)) / config.replaySpeed)
+
+	for started := time.Now(); ; started = started.Add(restart) {
+		for i := range config.requests {
+			next := started.Add(time.Duration(float64(config.requests[i].offset) / config.replaySpeed))
+			if next.Before(last.Add(minInterval)) {
+				next = last.Add(minInterval)
+			}
+
+			select {
+			// Exit signal
+			case <-ctx.Done():
+				applog.Info("Fire function exiting")
+				close(*comm)
+				return
+			// Time to send the next request
+			case <-time.After(time.Until(next)):
+				last = time.Now()
+				config.metrics.histReplayScheduleLag.WithLabelValues(config.metrics.labelValues...).Observe(last.Sub(next).Seconds())
+				config.metrics.summaryReplayScheduleLag.WithLabelValues(config.metrics.labelValues...).Observe(last.Sub(next).Seconds())
+				if len(*comm) < workersCannelSize {
+					*comm <- message{number: 1, request: &config.requests[i]}
+				} else {
+					config.metrics.channelFullEvents.WithLabelValues(config.metrics.labelValues...).Inc()
+				}
+			}
+		}
+	}
+}
+
+// Recordings without timing, like a single entry or a log without timestamps, would be replayed without delays,
+// so their requests are sent with -fire-rate instead
+func checkReplayTiming(config appConfig) (appConfig, error) {
+	if !config.replay || config.replaySpeed == 0 || config.requests[len(config.requests)-1].offset > 0 {
+		return config, nil
+	}
+	if config.fireRate <= 0 {
+		return config, fmt.Errorf("recorded requests have no timing to replay, set -fire-rate or use -har-speed 0 or -replay-speed 0")
+	}
+
+	applog.Warningf("Recorded requests have no timing to replay, sending them with -fire-rate %v", config.fireRate)
+	config.replaySpeed = 0
+
+	return config, nil
+}
+
+// Mean gap between the recorded requests, 0 if unknown
+func requestsMeanGap(requests []requestSpec) time.Duration {
+	if len(requests) < 2 {
+		return 0
+	}
+
+	return requests[len(requests)-1].offset / time.Duration(len(requests)-1)
+}
+
+// Rate of the recorded requests per second, 0 if unknown. N requests have N-1 gaps between them
+func requestsOriginalRate(requests []requestSpec) float64 {
+	gap := requestsMeanGap(requests)
+	if gap <= 0 {
+		return 0
+	}
+
+	return float64(time.Second) / float64(gap)
+}
+
+// Rate replay should achieve, 0 if unlimited or unknown
+func replayTargetRate(config appConfig) float64 {
+	if config.replaySpeed == 0 {
+		return float64(config.fireRate)
+	}
+
+	rate := requestsOriginalRate(config.requests) * config.replaySpeed
+	if config.fireRate > 0 && (rate == 0 || rate > float64(config.fireRate)) {
+		rate = float64(config.fireRate)
+	}
+
+	return rate
+}
+
+// Unique names of requests in the list
+func getRequestListNames(requests []requestSpec) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, request := range requests {
+		if !seen[request.name] {
+			seen[request.name] = true
+			names = append(names, request.name)
+		}
+	}
+
+	return names
+}
+
+// Pick the next request from the list, nil means the default request from CLI args
+func nextRequest(config appConfig, number uint64) *requestSpec {
+	if len(config.requests) == 0 {
+		return nil
+	}
+
+	return &config.requests[number%uint64(len(config.requests))]
+}
+
+// Count requests and errors and observe request duration per request name
+func observeNamedRequest(config appConfig, request *requestSpec, duration time.Duration, err error) {
+	if request == nil || request.name == "" {
+		return
+	}
+
+	localLabelValues := append(config.metrics.labelValues, request.name)
+	config.metrics.requestsByName.WithLabelValues(localLabelValues...).Inc()
+	config.metrics.histRequestsByNameDuration.WithLabelValues(localLabelValues...).Observe(duration.Seconds())
+	config.metrics.summaryRequestsByNameDuration.WithLabelValues(localLabelValues...).Observe(duration.Seconds())
+	if err != nil {
+		config.metrics.errorsByName.WithLabelValues(localLabelValues...).Inc()
+	}
+}
+
+// Copy of main labels map with request name label
+func requestNameLabels(config appConfig, name string) map[string]string {
+	labels := make(map[string]string, 0)
+	for k, v := range config.metrics.labels {
+		labels[k] = v
+	}
+	labels["request"] = name
+
+	return labels
+}
+
+// Sorted list of request names we have metrics for
+func getRequestNames(reg *prometheus.Registry) []string {
+	counts, err := getMetricValuesByLabel(reg, "minigun_requests_by_name_total", "request")
+	if err != nil {
+		applog.Errorf("Error getting request names: %s", err.Error())
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Collect per request reports
+func collectRequestsReport(config appConfig) map[string]requestReport {
+	result := make(map[string]requestReport)
+
+	counts, _ := getMetricValuesByLabel(registry, "minigun_requests_by_name_total", "request")
+	errors, _ := getMetricValuesByLabel(registry, "minigun_requests_errors_by_name_total", "request")
+
+	for _, name := range getRequestNames(registry) {
+		report := requestReport{Count: counts[name], Errors: errors[name]}
+		if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_requests_by_name_duration_seconds", requestNameLabels(config, name)); err == nil {
+			report.DurationSecondsMean = mean
+			report.DurationSecondsQuantiles = jsonizeFloatMap(quantiles)
+		}
+		result[name] = report
+	}
+
+	return result
+}
+
+// Get per request table for the text report
+func reportRequestsText(config appConfig) string {
+	var outMatrix printMatrix
+
+	names := getRequestNames(registry)
+	if len(names) == 0 {
+		return ""
+	}
+
+	counts, _ := getMetricValuesByLabel(registry, "minigun_requests_by_name_total", "request")
+	errors, _ := getMetricValuesByLabel(registry, "minigun_requests_errors_by_name_total", "request")
+
+	for _, name := range names {
+		var latencies printMatrix
+		latencies = addSummaryToReport(latencies, name, registry, "minigun_requests_by_name_duration_seconds", requestNameLabels(config, name))
+		if len(latencies) == 0 {
+			continue
+		}
+
+		row := printRow{name, fmt.Sprintf("%v", counts[name]), fmt.Sprintf("%v", errors[name])}
+		row = append(row, latencies[0][1:]...)
+		outMatrix = append(outMatrix, row)
+	}
+
+	header := printRow{"Request", "Count", "Errors", "Mean", "Median", "P90", "P95", "P99"}
+
+	return formatPrintMatrix(header, outMatrix, true, config.report == "table")
+}
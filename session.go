@@ -0,0 +1,253 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Variable reference in target URL, headers and body, like {{token}}
+var sessionVariableRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+var sessionVariableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Rule to extract a variable from responses
+type extractRule struct {
+	name   string
+	source string
+	expr   string
+	path   []any
+	regexp *regexp.Regexp
+}
+
+// Custom type to parse "name=source:expression" extract rules as multiple flag cli args
+type extractRules []extractRule
+
+func (rules *extractRules) String() string {
+	return "Extract rules"
+}
+
+func (rules *extractRules) Set(value string) error {
+	rule, err := parseExtractRule(value)
+	if err != nil {
+		return err
+	}
+
+	*rules = append(*rules, rule)
+
+	return nil
+}
+
+// Per worker session. Cookies and variables are never shared between workers, so every worker acts as a separate user
+type workerSession struct {
+	jar       http.CookieJar
+	variables map[string]string
+}
+
+// Parse "name=header:X-Token", "name=json:$.data.token" or "name=regex:token=(\w+)" rule
+func parseExtractRule(value string) (extractRule, error) {
+	name, rest, ok := strings.Cut(value, "=")
+	source, expr, ok2 := strings.Cut(rest, ":")
+	if !ok || !ok2 || expr == "" {
+		return extractRule{}, fmt.Errorf("wrong extract argument %q, expected 'name=source:expression'", value)
+	}
+
+	if !sessionVariableNameRegexp.MatchString(name) {
+		return extractRule{}, fmt.Errorf("wrong variable name %q, only letters, digits and underscores are allowed", name)
+	}
+
+	rule := extractRule{name: name, source: source, expr: expr}
+	switch source {
+	case "header":
+	case "json":
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			return rule, err
+		}
+		rule.path = path
+	case "regex":
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return rule, err
+		}
+		rule.regexp = re
+	default:
+		return rule, fmt.Errorf("unsupported extract source %q, supported are header, json and regex", source)
+	}
+
+	return rule, nil
+}
+
+// Parse simple JSON path like $.data.items[0].id into object keys and array indexes
+func parseJSONPath(expr string) ([]any, error) {
+	path := make([]any, 0)
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "$"), ".")
+
+	for _, part := range strings.Split(expr, ".") {
+		key, indexes, _ := strings.Cut(part, "[")
+		if key != "" {
+			path = append(path, key)
+		}
+
+		if indexes == "" {
+			if key == "" {
+				return nil, fmt.Errorf("wrong JSON path %q", expr)
+			}
+			continue
+		}
+
+		for _, index := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+			i, err := strconv.Atoi(index)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("wrong array index %q in JSON path %q", index, expr)
+			}
+			path = append(path, i)
+		}
+	}
+
+	return path, nil
+}
+
+// Find JSON path value, strings are returned as is, everything else as JSON
+func lookupJSONPath(doc any, path []any) (string, bool) {
+	for _, step := range path {
+		switch step := step.(type) {
+		case string:
+			object, ok := doc.(map[string]any)
+			if !ok {
+				return "", false
+			}
+			if doc, ok = object[step]; !ok {
+				return "", false
+			}
+		case int:
+			array, ok := doc.([]any)
+			if !ok || step >= len(array) {
+				return "", false
+			}
+			doc = array[step]
+		}
+	}
+
+	switch value := doc.(type) {
+	case string:
+		return value, true
+	case nil:
+		return "", false
+	default:
+		data, err := json.Marshal(value)
+		return string(data), err == nil
+	}
+}
+
+// Create worker session, returns nil if there's nothing to keep between requests
+func newWorkerSession(config appConfig) *workerSession {
+	if !config.cookieJar && len(config.extractRules) == 0 {
+		return nil
+	}
+
+	session := &workerSession{variables: make(map[string]string)}
+	if config.cookieJar {
+		// Never fails without options
+		session.jar, _ = cookiejar.New(nil)
+	}
+
+	return session
+}
+
+// Substitute session variables into target, headers and payload.
+// Unknown variables are left as is, so the server gets an obviously wrong request
+func (s *workerSession) expand(config appConfig, data []byte) (appConfig, []byte) {
+	replace := func(name string) (string, bool) {
+		if value, ok := s.variables[name]; ok {
+			return value, true
+		}
+		config.metrics.sessionUnresolvedVariables.WithLabelValues(append(config.metrics.labelValues, name)...).Inc()
+		return "", false
+	}
+
+	expandString := func(value string, escape func(string) string) string {
+		if !strings.Contains(value, "{{") {
+			return value
+		}
+		return sessionVariableRegexp.ReplaceAllStringFunc(value, func(match string) string {
+			if value, ok := replace(sessionVariableRegexp.FindStringSubmatch(match)[1]); ok {
+				return escape(value)
+			}
+			return match
+		})
+	}
+	raw := func(value string) string { return value }
+
+	// Values are escaped in the target URL, so they can't change its path or add query parameters
+	path, query, hasQuery := strings.Cut(config.sendEndpoint, "?")
+	config.sendEndpoint = expandString(path, url.PathEscape)
+	if hasQuery {
+		config.sendEndpoint += "?" + expandString(query, url.QueryEscape)
+	}
+
+	headers := make(httpHeaders, len(config.sendHTTPHeaders))
+	for key, value := range config.sendHTTPHeaders {
+		headers[key] = expandString(value, raw)
+	}
+	config.sendHTTPHeaders = headers
+
+	if bytes.Contains(data, []byte("{{")) {
+		data = []byte(expandString(string(data), raw))
+		config.sendPayload = data
+
+		// Templated payload has to be compressed per request
+		config.sendPayloadCompressed = nil
+	}
+
+	return config, data
+}
+
+// Extract variables from response for the next requests. Rules which don't match keep previous values
+func (s *workerSession) extract(config appConfig, resp *http.Response, body []byte) {
+	var doc any
+	parsed := false
+
+	for _, rule := range config.extractRules {
+		var value string
+		var found bool
+
+		switch rule.source {
+		case "header":
+			value = resp.Header.Get(rule.expr)
+			found = value != ""
+		case "json":
+			if !parsed {
+				parsed = true
+				if err := json.Unmarshal(body, &doc); err != nil {
+					doc = nil
+				}
+			}
+			value, found = lookupJSONPath(doc, rule.path)
+		case "regex":
+			if m := rule.regexp.FindSubmatch(body); m != nil {
+				// First capture group if there's one, whole match otherwise
+				value, found = string(m[min(1, len(m)-1)]), true
+			}
+		}
+
+		if !found {
+			continue
+		}
+
+		applog.Infof("Extracted variable %q from response", rule.name)
+		s.variables[rule.name] = value
+		config.metrics.sessionExtractedVariables.WithLabelValues(append(config.metrics.labelValues, rule.name)...).Inc()
+	}
+}
@@ -0,0 +1,313 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const sigV4Algorithm = "AWS4-HMAC-SHA256"
+const sigV4TimeFormat = "20060102T150405Z"
+
+// Request signer runs for every request right before it's sent, after all headers are set
+type requestSigner interface {
+	sign(req *http.Request, body []byte, now time.Time) error
+}
+
+// AWS credentials
+type awsCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// AWS Signature Version 4 signer
+type sigV4Signer struct {
+	credentials awsCredentials
+	region      string
+	service     string
+}
+
+// HMAC signer with signature over method, path with query, timestamp and body
+type hmacSigner struct {
+	secret          []byte
+	hash            func() hash.Hash
+	encoding        string
+	header          string
+	timestampHeader string
+}
+
+// Init request signer from config, returns nil if signing is not configured
+func initRequestSigner(config appConfig) (requestSigner, error) {
+	switch config.sign {
+	case "":
+		return nil, nil
+
+	case "sigv4":
+		credentials, err := loadAWSCredentials(config.sigV4Profile)
+		if err != nil {
+			return nil, err
+		}
+
+		region := config.sigV4Region
+		for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+			if region == "" {
+				region = os.Getenv(env)
+			}
+		}
+		if region == "" || config.sigV4Service == "" {
+			return nil, fmt.Errorf("-sigv4-region and -sigv4-service are required for sigv4 signing")
+		}
+
+		return &sigV4Signer{credentials: credentials, region: region, service: config.sigV4Service}, nil
+
+	case "hmac":
+		secret := config.hmacSecret
+		if config.hmacSecretFile != "" {
+			data, err := os.ReadFile(config.hmacSecretFile)
+			if err != nil {
+				return nil, fmt.Errorf("error reading -hmac-secret-file: %s", err.Error())
+			}
+			secret = strings.TrimRight(string(data), "\r\n")
+		}
+		if secret == "" {
+			return nil, fmt.Errorf("-hmac-secret or -hmac-secret-file is required for hmac signing")
+		}
+
+		s := &hmacSigner{secret: []byte(secret), header: config.hmacHeader, timestampHeader: config.hmacTimestampHeader, encoding: config.hmacEncoding}
+		switch config.hmacAlgorithm {
+		case "sha256":
+			s.hash = sha256.New
+		case "sha512":
+			s.hash = sha512.New
+		default:
+			return nil, fmt.Errorf("unsupported HMAC algorithm %q, supported are sha256 and sha512", config.hmacAlgorithm)
+		}
+		if s.encoding != "hex" && s.encoding != "base64" {
+			return nil, fmt.Errorf("unsupported HMAC encoding %q, supported are hex and base64", s.encoding)
+		}
+
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("unsupported signing %q, supported are sigv4 and hmac", config.sign)
+}
+
+// Load AWS credentials from env variables, or from the shared credentials file
+func loadAWSCredentials(profile string) (awsCredentials, error) {
+	credentials := awsCredentials{
+		accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if credentials.accessKeyID != "" && credentials.secretAccessKey != "" && profile == "" {
+		return credentials, nil
+	}
+
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	fileName := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if fileName == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return credentials, err
+		}
+		fileName = filepath.Join(home, ".aws", "credentials")
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return credentials, fmt.Errorf("no AWS credentials in env variables and can't read shared credentials file: %s", err.Error())
+	}
+	defer file.Close()
+
+	credentials = awsCredentials{}
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section != profile {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			credentials.accessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			credentials.secretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			credentials.sessionToken = strings.TrimSpace(value)
+		}
+	}
+
+	if credentials.accessKeyID == "" || credentials.secretAccessKey == "" {
+		return credentials, fmt.Errorf("no AWS credentials for profile %q in %s", profile, fileName)
+	}
+
+	return credentials, nil
+}
+
+// Sign request with AWS Signature Version 4, see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv-create-signed-request.html
+func (s *sigV4Signer) sign(req *http.Request, body []byte, now time.Time) error {
+	amzDate := now.UTC().Format(sigV4TimeFormat)
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.credentials.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.credentials.sessionToken)
+	}
+
+	bodyHash := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(bodyHash[:])
+
+	// S3 requires payload hash header, other services accept it but don't need it
+	if s.service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	// Sign host, content type and all AWS headers. Other headers could be changed by proxies
+	headers := map[string]string{"host": host}
+	for key, values := range req.Header {
+		name := strings.ToLower(key)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.Join(values, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.Join(strings.Fields(headers[name]), " ") + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// S3 paths are encoded once, other services need them encoded twice
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if s.service != "s3" {
+		path = sigV4Escape(path, false)
+	}
+
+	canonicalRequest := strings.Join([]string{
+		http3WireMethod(req.Method),
+		path,
+		sigV4CanonicalQuery(req),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/" + s.service + "/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := sigV4Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSum(sha256.New, []byte("AWS4"+s.credentials.secretAccessKey), date)
+	for _, part := range []string{s.region, s.service, "aws4_request"} {
+		key = hmacSum(sha256.New, key, part)
+	}
+	signature := hex.EncodeToString(hmacSum(sha256.New, key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.credentials.accessKeyID, scope, signedHeaders, signature))
+
+	return nil
+}
+
+// Query parameters sorted by name and value, with strict URI encoding
+func sigV4CanonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+	params := make([]string, 0)
+	for key, values := range query {
+		for _, value := range values {
+			params = append(params, sigV4Escape(key, true)+"="+sigV4Escape(value, true))
+		}
+	}
+	sort.Strings(params)
+
+	return strings.Join(params, "&")
+}
+
+// URI encode everything except unreserved characters, and slashes if it's not a query part
+func sigV4Escape(s string, encodeSlash bool) string {
+	var result strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			result.WriteByte(c)
+		} else {
+			fmt.Fprintf(&result, "%%%02X", c)
+		}
+	}
+
+	return result.String()
+}
+
+// Sign request with HMAC over "METHOD\nPATH?QUERY\nTIMESTAMP\nBODY"
+func (s *hmacSigner) sign(req *http.Request, body []byte, now time.Time) error {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set(s.timestampHeader, timestamp)
+
+	message := http3WireMethod(req.Method) + "\n" + req.URL.RequestURI() + "\n" + timestamp + "\n" + string(body)
+	signature := hmacSum(s.hash, s.secret, message)
+
+	if s.encoding == "base64" {
+		req.Header.Set(s.header, base64.StdEncoding.EncodeToString(signature))
+	} else {
+		req.Header.Set(s.header, hex.EncodeToString(signature))
+	}
+
+	return nil
+}
+
+// HMAC of the message
+func hmacSum(h func() hash.Hash, key []byte, message string) []byte {
+	mac := hmac.New(h, key)
+	mac.Write([]byte(message))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,90 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// Max number of addresses we're ready to expand from CIDRs
+const maxSourceIPs = 65536
+
+// Parse comma separated list of local IP addresses and CIDRs
+func parseSourceIPs(value string) ([]string, error) {
+	result := make([]string, 0)
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if !strings.Contains(item, "/") {
+			addr, err := netip.ParseAddr(item)
+			if err != nil {
+				return nil, fmt.Errorf("wrong source IP %q: %s", item, err.Error())
+			}
+			result = append(result, addr.String())
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, fmt.Errorf("wrong source IP CIDR %q: %s", item, err.Error())
+		}
+		prefix = prefix.Masked()
+
+		// Skip network and broadcast addresses for IPv4 networks
+		skipEdges := prefix.Addr().Is4() && prefix.Bits() < 31
+
+		for addr := prefix.Addr(); prefix.Contains(addr); addr = addr.Next() {
+			if skipEdges && (addr == prefix.Addr() || !prefix.Contains(addr.Next())) {
+				continue
+			}
+
+			result = append(result, addr.String())
+			if len(result) > maxSourceIPs {
+				return nil, fmt.Errorf("too many source IPs, max is %v", maxSourceIPs)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Pick source IP for the worker, so workers are spread across all source IPs
+func workerSourceIP(config appConfig, id int) string {
+	if len(config.sourceIPs) == 0 {
+		return ""
+	}
+
+	return config.sourceIPs[id%len(config.sourceIPs)]
+}
+
+// Local TCP address to bind to, nil means default
+func sourceTCPAddr(config appConfig) net.Addr {
+	if config.sourceIP == "" {
+		return nil
+	}
+
+	return &net.TCPAddr{IP: net.ParseIP(config.sourceIP)}
+}
+
+// Count requests and errors per source IP
+func observeSourceIPRequest(config appConfig, err error) {
+	if config.sourceIP == "" {
+		return
+	}
+
+	localLabelValues := append(config.metrics.labelValues, config.sourceIP)
+	config.metrics.requestsBySourceIP.WithLabelValues(localLabelValues...).Inc()
+	if err != nil {
+		config.metrics.errorsBySourceIP.WithLabelValues(localLabelValues...).Inc()
+	}
+}
@@ -0,0 +1,163 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// From 64 KB/s to 16 GB/s, upload throughput per request
+var throughputBuckets = []float64{64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20, 64 << 20, 256 << 20, 1 << 30, 4 << 30, 16 << 30}
+
+// Streamed request body, read in chunks with optional bandwidth limit. Tracks upload throughput
+type uploadStream struct {
+	reader    io.Reader
+	closer    io.Closer
+	chunkSize int
+	bandwidth int64
+
+	// Transport reads and closes the stream in its own goroutine, even after the response is received
+	started   atomic.Int64
+	finished  atomic.Int64
+	bytes     atomic.Int64
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Repeats the same block until size is reached, so generated streams of any size don't need memory
+type repeatReader struct {
+	block     []byte
+	offset    int
+	remaining int64
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+
+	n := 0
+	for n < len(p) {
+		copied := copy(p[n:], r.block[r.offset:])
+		n += copied
+		r.offset = (r.offset + copied) % len(r.block)
+	}
+	r.remaining -= int64(n)
+
+	return n, nil
+}
+
+// Open a new request body stream, from -send-file or generated
+func newUploadStream(config appConfig) (*uploadStream, error) {
+	stream := &uploadStream{chunkSize: config.sendChunkSize, bandwidth: config.sendBandwidth, done: make(chan struct{})}
+
+	if config.sendFile != "" {
+		file, err := os.Open(config.sendFile)
+		if err != nil {
+			return nil, err
+		}
+		stream.reader, stream.closer = file, file
+	} else {
+		stream.reader = &repeatReader{block: config.sendStreamBlock, remaining: config.sendStreamSize}
+	}
+
+	return stream, nil
+}
+
+// Read up to a chunk, then wait if we're ahead of the bandwidth limit
+func (s *uploadStream) Read(p []byte) (int, error) {
+	s.started.CompareAndSwap(0, time.Now().UnixNano())
+
+	if len(p) > s.chunkSize {
+		p = p[:s.chunkSize]
+	}
+
+	n, err := s.reader.Read(p)
+	bytes := s.bytes.Add(int64(n))
+
+	if s.bandwidth > 0 && n > 0 {
+		expected := time.Duration(float64(bytes) / float64(s.bandwidth) * float64(time.Second))
+		if wait := expected - time.Since(time.Unix(0, s.started.Load())); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+
+	if err == io.EOF {
+		s.finished.CompareAndSwap(0, time.Now().UnixNano())
+	}
+
+	return n, err
+}
+
+// HTTP/1.1 transport copies the body with WriteTo, so every write is a chunk of -send-chunk-size
+func (s *uploadStream) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	buf := make([]byte, s.chunkSize)
+
+	for {
+		n, err := s.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return written, err
+			}
+			written += int64(n)
+		}
+
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+// Transport closes the stream when it's done with it, maybe more than once
+func (s *uploadStream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		if s.closer != nil {
+			err = s.closer.Close()
+		}
+		close(s.done)
+	})
+
+	return err
+}
+
+// Wait until the transport closes the stream, or the timeout if there's one
+func (s *uploadStream) wait(timeout time.Duration) {
+	if timeout <= 0 {
+		<-s.done
+		return
+	}
+
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+	}
+}
+
+// Observe upload throughput of a fully sent stream
+func observeUploadStream(config appConfig, stream *uploadStream) {
+	started, finished := stream.started.Load(), stream.finished.Load()
+	if finished == 0 {
+		return
+	}
+
+	if seconds := time.Duration(finished - started).Seconds(); seconds > 0 {
+		config.metrics.histUploadThroughput.WithLabelValues(config.metrics.labelValues...).Observe(float64(stream.bytes.Load()) / seconds)
+		config.metrics.summaryUploadThroughput.WithLabelValues(config.metrics.labelValues...).Observe(float64(stream.bytes.Load()) / seconds)
+	}
+}
@@ -0,0 +1,232 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Target selection strategies
+var targetStrategies = []string{"round-robin", "random", "weighted", "least-outstanding"}
+
+// A single target with its weight and requests in flight
+type target struct {
+	url         string
+	weight      int
+	current     int
+	outstanding int
+}
+
+// Picks targets for requests of all workers
+type targetBalancer struct {
+	strategy string
+	targets  []*target
+	next     int
+	mu       sync.Mutex
+}
+
+// Load targets from a comma separated list of URLs, or from a file with one target per line.
+// Every target could have a weight after a space, like "http://backend-1:8080 3"
+func loadTargets(value string, strategy string) (*targetBalancer, error) {
+	supported := false
+	for _, s := range targetStrategies {
+		supported = supported || s == strategy
+	}
+	if !supported {
+		return nil, fmt.Errorf("unsupported strategy %q, supported are %s", strategy, strings.Join(targetStrategies, ", "))
+	}
+
+	lines := strings.Split(value, ",")
+	if !strings.Contains(value, "://") {
+		data, err := os.ReadFile(value)
+		if err != nil {
+			return nil, err
+		}
+		lines = strings.Split(string(data), "\n")
+	}
+
+	balancer := &targetBalancer{strategy: strategy}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		t := &target{url: fields[0], weight: 1}
+		if err := validateUrl(t.url); err != nil {
+			return nil, err
+		}
+
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("wrong target %q, expected 'URL [weight]'", line)
+		}
+		if len(fields) == 2 {
+			weight, err := strconv.Atoi(fields[1])
+			if err != nil || weight <= 0 {
+				return nil, fmt.Errorf("wrong weight of target %q, expected a number > 0", line)
+			}
+			t.weight = weight
+		}
+
+		balancer.targets = append(balancer.targets, t)
+	}
+
+	if len(balancer.targets) == 0 {
+		return nil, fmt.Errorf("no targets in %q", value)
+	}
+
+	return balancer, nil
+}
+
+// Pick the target for the next request, it's outstanding until done() is called
+func (b *targetBalancer) pick(rng *rand.Rand) *target {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var picked *target
+	switch b.strategy {
+	case "random":
+		picked = b.targets[rng.Intn(len(b.targets))]
+
+	case "weighted":
+		// Smooth weighted round-robin, like nginx does: heavier targets are picked more often, but not in a row
+		total := 0
+		for _, t := range b.targets {
+			t.current += t.weight
+			total += t.weight
+			if picked == nil || t.current > picked.current {
+				picked = t
+			}
+		}
+		picked.current -= total
+
+	case "least-outstanding":
+		// Ties are broken in round-robin order, so idle targets share requests evenly
+		for i := range b.targets {
+			t := b.targets[(b.next+i)%len(b.targets)]
+			if picked == nil || t.outstanding < picked.outstanding {
+				picked = t
+			}
+		}
+		b.next++
+
+	default:
+		picked = b.targets[b.next%len(b.targets)]
+		b.next++
+	}
+
+	picked.outstanding++
+
+	return picked
+}
+
+// Request to the target is complete
+func (b *targetBalancer) done(t *target) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t.outstanding--
+}
+
+// Count requests and errors and observe request duration per target
+func observeTargetRequest(config appConfig, t *target, duration time.Duration, err error) {
+	if t == nil {
+		return
+	}
+
+	localLabelValues := append(config.metrics.labelValues, t.url)
+	config.metrics.requestsByTarget.WithLabelValues(localLabelValues...).Inc()
+	config.metrics.histRequestsByTargetDuration.WithLabelValues(localLabelValues...).Observe(duration.Seconds())
+	config.metrics.summaryRequestsByTargetDuration.WithLabelValues(localLabelValues...).Observe(duration.Seconds())
+	if err != nil {
+		config.metrics.errorsByTarget.WithLabelValues(localLabelValues...).Inc()
+	}
+}
+
+// Copy of main labels map with target label
+func targetLabels(config appConfig, url string) map[string]string {
+	labels := make(map[string]string, 0)
+	for k, v := range config.metrics.labels {
+		labels[k] = v
+	}
+	labels["target"] = url
+
+	return labels
+}
+
+// Sorted list of targets we have metrics for
+func getTargetNames(reg *prometheus.Registry) []string {
+	counts, err := getMetricValuesByLabel(reg, "minigun_requests_by_target_total", "target")
+	if err != nil {
+		applog.Errorf("Error getting targets: %s", err.Error())
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Per target JSON report
+func collectTargetsReport(config appConfig) map[string]requestReport {
+	result := make(map[string]requestReport)
+
+	counts, _ := getMetricValuesByLabel(registry, "minigun_requests_by_target_total", "target")
+	errors, _ := getMetricValuesByLabel(registry, "minigun_requests_errors_by_target_total", "target")
+
+	for _, name := range getTargetNames(registry) {
+		report := requestReport{Count: counts[name], Errors: errors[name]}
+		if _, _, mean, quantiles, err := getSummaryValues(registry, "minigun_requests_by_target_duration_seconds", targetLabels(config, name)); err == nil {
+			report.DurationSecondsMean = mean
+			report.DurationSecondsQuantiles = jsonizeFloatMap(quantiles)
+		}
+		result[name] = report
+	}
+
+	return result
+}
+
+// Get per target table for the text report
+func reportTargetsText(config appConfig) string {
+	var outMatrix printMatrix
+
+	names := getTargetNames(registry)
+	if len(names) == 0 {
+		return ""
+	}
+
+	counts, _ := getMetricValuesByLabel(registry, "minigun_requests_by_target_total", "target")
+	errors, _ := getMetricValuesByLabel(registry, "minigun_requests_errors_by_target_total", "target")
+
+	for _, name := range names {
+		var latencies printMatrix
+		latencies = addSummaryToReport(latencies, name, registry, "minigun_requests_by_target_duration_seconds", targetLabels(config, name))
+		if len(latencies) == 0 {
+			continue
+		}
+
+		row := printRow{name, fmt.Sprintf("%v", counts[name]), fmt.Sprintf("%v", errors[name])}
+		row = append(row, latencies[0][1:]...)
+		outMatrix = append(outMatrix, row)
+	}
+
+	header := printRow{"Target", "Count", "Errors", "Mean", "Median", "P90", "P95", "P99"}
+
+	return formatPrintMatrix(header, outMatrix, true, config.report == "table")
+}
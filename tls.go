@@ -0,0 +1,159 @@
+// Simple HTTP benchmark tool
+//
+// @authors Minigun Maintainers
+// @copyright 2020 Wayfair, LLC -- All rights reserved.
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Supported TLS versions for CLI args
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Init TLS config shared by all workers. Files are loaded only once here
+func initTLSConfig(config appConfig) (*tls.Config, error) {
+	var err error
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify:     config.insecure,
+		ServerName:             config.tlsServerName,
+		SessionTicketsDisabled: !config.tlsSessionResumption,
+	}
+
+	// Client certificate for mutual TLS
+	if config.tlsCert != "" || config.tlsKey != "" {
+		if config.tlsCert == "" || config.tlsKey == "" {
+			return nil, fmt.Errorf("both -tls-cert and -tls-key must be specified")
+		}
+
+		cert, err := tls.LoadX509KeyPair(config.tlsCert, config.tlsKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %s", err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// Custom CA
+	if config.tlsCA != "" {
+		data, err := os.ReadFile(config.tlsCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %q: %s", config.tlsCA, err.Error())
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificates found in CA file %q", config.tlsCA)
+		}
+	}
+
+	if tlsConfig.MinVersion, err = parseTLSVersion(config.tlsMinVersion); err != nil {
+		return nil, err
+	}
+
+	if tlsConfig.MaxVersion, err = parseTLSVersion(config.tlsMaxVersion); err != nil {
+		return nil, err
+	}
+
+	if tlsConfig.CipherSuites, err = parseTLSCipherSuites(config.tlsCiphers); err != nil {
+		return nil, err
+	}
+
+	return tlsConfig, nil
+}
+
+// Clone shared TLS config for a client, every client gets its own session cache
+func clientTLSConfig(config appConfig) *tls.Config {
+	if config.tlsConfig == nil {
+		return &tls.Config{InsecureSkipVerify: config.insecure}
+	}
+
+	tlsConfig := config.tlsConfig.Clone()
+	if config.tlsSessionResumption {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+
+	return tlsConfig
+}
+
+// Convert TLS version from CLI arg, empty string means Go default
+func parseTLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+
+	if v, ok := tlsVersions[version]; ok {
+		return v, nil
+	}
+
+	return 0, fmt.Errorf("unsupported TLS version %q, supported versions are 1.0, 1.1, 1.2 and 1.3", version)
+}
+
+// Convert comma separated list of cipher suite names, empty string means Go default
+func parseTLSCipherSuites(list string) ([]uint16, error) {
+	var result []uint16
+
+	if list == "" {
+		return result, nil
+	}
+
+	suites := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		suites[suite.Name] = suite.ID
+	}
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		id, ok := suites[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS cipher suite %q", name)
+		}
+		result = append(result, id)
+	}
+
+	return result, nil
+}
+
+// Handshake type label value
+func tlsHandshakeType(cs tls.ConnectionState) string {
+	if cs.DidResume {
+		return "resumed"
+	}
+	return "full"
+}
+
+// Record negotiated TLS parameters, handshake type and duration and certificate expiry
+func observeTLSConnectionState(config appConfig, cs tls.ConnectionState, handshake time.Duration) {
+	handshakeType := tlsHandshakeType(cs)
+
+	alpn := cs.NegotiatedProtocol
+	if alpn == "" {
+		alpn = "none"
+	}
+
+	localLabelValues := append(config.metrics.labelValues, tls.VersionName(cs.Version), tls.CipherSuiteName(cs.CipherSuite), alpn, handshakeType)
+	config.metrics.tlsConnections.WithLabelValues(localLabelValues...).Inc()
+
+	localLabelValues = append(config.metrics.labelValues, handshakeType)
+	config.metrics.histTLSHandshakeByType.WithLabelValues(localLabelValues...).Observe(handshake.Seconds())
+	config.metrics.summaryTLSHandshakeByType.WithLabelValues(localLabelValues...).Observe(handshake.Seconds())
+
+	if len(cs.PeerCertificates) > 0 {
+		cert := cs.PeerCertificates[0]
+		localLabelValues = append(config.metrics.labelValues, cert.Subject.String())
+		config.metrics.tlsCertificateExpiry.WithLabelValues(localLabelValues...).Set(float64(cert.NotAfter.Unix()))
+	}
+
+	applog.Infof("TLS %s handshake, version: %s, cipher: %s, ALPN: %s", handshakeType, tls.VersionName(cs.Version), tls.CipherSuiteName(cs.CipherSuite), alpn)
+}